@@ -1,20 +1,10 @@
 package main
 
 import (
-	"fmt"
-	"monkeyInterpreter/pkg/repl"
+	"monkeyInterpreter/pkg/cli"
 	"os"
-	"os/user"
 )
 
 func main() {
-	usr, err := user.Current()
-
-	if err != nil {
-		panic(err)
-	}
-
-	fmt.Printf("Hello %s! This is the Monkey programming language!\n", usr.Username)
-	fmt.Printf("Feel free to type in commands \n")
-	repl.Start(os.Stdin, os.Stdout)
+	os.Exit(cli.Run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
 }
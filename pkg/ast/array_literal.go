@@ -0,0 +1,54 @@
+package ast
+
+import (
+	"bytes"
+	"monkeyInterpreter/pkg/token"
+	"strings"
+)
+
+type ArrayLiteral struct {
+	// Token is the [ that opens the array
+	Token    token.Token
+	Elements []Expression
+
+	// Rbracket is the ] that closes the array, kept so the span of the array covers it
+	Rbracket token.Token
+}
+
+func (al *ArrayLiteral) TokenLiteral() string {
+	return al.Token.Literal
+}
+
+func (al *ArrayLiteral) String() string {
+	var out bytes.Buffer
+
+	elements := []string{}
+
+	for _, el := range al.Elements {
+		elements = append(elements, el.String())
+	}
+
+	out.WriteString("[")
+	out.WriteString(strings.Join(elements, ", "))
+	out.WriteString("]")
+
+	return out.String()
+}
+
+func (al *ArrayLiteral) Pos() token.Position {
+	return al.Token.Pos
+}
+
+func (al *ArrayLiteral) End() token.Position {
+	if al.Rbracket.End.IsValid() {
+		return al.Rbracket.End
+	}
+
+	if len(al.Elements) > 0 {
+		return al.Elements[len(al.Elements)-1].End()
+	}
+
+	return al.Token.End
+}
+
+func (al *ArrayLiteral) expressionNode() {}
@@ -1,8 +1,16 @@
 package ast
 
+import "monkeyInterpreter/pkg/token"
+
 type Node interface {
 	TokenLiteral() string
 	String() string
+
+	// Pos is the position of the first character belonging to the node
+	Pos() token.Position
+
+	// End is the position immediately after the last character belonging to the node
+	End() token.Position
 }
 
 type Statement interface {
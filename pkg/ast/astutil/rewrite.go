@@ -0,0 +1,318 @@
+package astutil
+
+import (
+	"fmt"
+	"monkeyInterpreter/pkg/ast"
+	"slices"
+)
+
+// ApplyFunc is called by Apply for every node, with a Cursor pointing at it
+type ApplyFunc func(cursor *Cursor) bool
+
+// Apply traverses the tree below root depth first, in the order the nodes were written, and returns root, or what it
+// was replaced with.  Missing parts are skipped, as they are by ast.Walk.
+//
+// pre is called for a node before its children and post after them, and either may be nil.  When pre returns false
+// the children of the node are skipped and so is post, and when post returns false the traversal stops altogether.
+// Both can change the tree through the Cursor, and when pre replaces a node it is the children of the new node that
+// are traversed.
+func Apply(root ast.Node, pre ApplyFunc, post ApplyFunc) (result ast.Node) {
+	var a = &application{pre: pre, post: post}
+
+	defer func() {
+		if r := recover(); r != nil && r != abort {
+			panic(r)
+		}
+	}()
+
+	result = root
+	a.apply(nil, "", root, func(node ast.Node) { result = node }, nil)
+
+	return result
+}
+
+// abort is panicked with to unwind the traversal once post has asked for it to stop
+var abort = new(int)
+
+// Cursor describes the node being visited by Apply, and where it is in the tree
+type Cursor struct {
+	parent ast.Node
+	name   string
+	node   ast.Node
+
+	// replace puts a node in the place of one that is not in a list
+	replace func(ast.Node)
+
+	// list and iter are set when the node is an element of a list, such as the statements of a block
+	list list
+	iter *iterator
+}
+
+// Node returns the node being visited, which is nil once it has been deleted
+func (c *Cursor) Node() ast.Node {
+	return c.node
+}
+
+// Parent returns the node holding the node being visited, which is nil for the root
+func (c *Cursor) Parent() ast.Node {
+	return c.parent
+}
+
+// Name returns the name of the field of the parent holding the node being visited, such as "Left" or "Statements"
+func (c *Cursor) Name() string {
+	return c.name
+}
+
+// Index returns the position of the node being visited in the list holding it, or -1 if it is not in a list
+func (c *Cursor) Index() int {
+	if c.iter == nil {
+		return -1
+	}
+
+	return c.iter.index
+}
+
+// Replace puts node in the place of the node being visited.  It panics if node can't go there, such as a statement in
+// place of an expression.
+func (c *Cursor) Replace(node ast.Node) {
+	c.mustNotBeDeleted("Replace")
+
+	if c.iter != nil {
+		c.list.set(c.iter.index, node)
+	} else {
+		c.replace(node)
+	}
+
+	c.node = node
+}
+
+// Delete removes the node being visited from the list holding it.  Its children are not visited, and neither is post
+// called for it.  Delete panics if the node is not in a list.
+func (c *Cursor) Delete() {
+	c.mustBeInList("Delete")
+
+	c.list.delete(c.iter.index)
+	c.iter.step -= 1
+	c.node = nil
+}
+
+// InsertBefore puts node into the list holding the node being visited, in front of it.  The inserted node is not
+// visited.  InsertBefore panics if the node being visited is not in a list.
+func (c *Cursor) InsertBefore(node ast.Node) {
+	c.mustBeInList("InsertBefore")
+
+	c.list.insert(c.iter.index, node)
+	c.iter.index += 1
+}
+
+// InsertAfter puts node into the list holding the node being visited, after it.  The inserted node is not visited.
+// InsertAfter panics if the node being visited is not in a list.
+func (c *Cursor) InsertAfter(node ast.Node) {
+	c.mustBeInList("InsertAfter")
+
+	c.list.insert(c.iter.index+1, node)
+	c.iter.step += 1
+}
+
+func (c *Cursor) mustBeInList(method string) {
+	c.mustNotBeDeleted(method)
+
+	if c.parent == nil {
+		panic(fmt.Sprintf("astutil: %s used on the root, which is not in a list", method))
+	}
+
+	if c.iter == nil {
+		panic(fmt.Sprintf("astutil: %s used on %s of %T, which is not a list", method, c.name, c.parent))
+	}
+}
+
+func (c *Cursor) mustNotBeDeleted(method string) {
+	if c.node == nil {
+		panic(fmt.Sprintf("astutil: %s used after the node was deleted", method))
+	}
+}
+
+// iterator is the position of the cursor in a list
+type iterator struct {
+	index int
+
+	// step is how far to move on to reach the next element not yet visited, once the current one is done
+	step int
+}
+
+// list edits one of the lists of nodes in the tree, whatever type its elements are
+type list interface {
+	len() int
+	at(i int) ast.Node
+	set(i int, node ast.Node)
+	insert(i int, node ast.Node)
+	delete(i int)
+}
+
+type nodeList[T ast.Node] struct {
+	parent ast.Node
+	name   string
+	items  *[]T
+}
+
+func (l nodeList[T]) len() int {
+	return len(*l.items)
+}
+
+func (l nodeList[T]) at(i int) ast.Node {
+	return (*l.items)[i]
+}
+
+func (l nodeList[T]) set(i int, node ast.Node) {
+	(*l.items)[i] = l.element(node)
+}
+
+func (l nodeList[T]) insert(i int, node ast.Node) {
+	*l.items = slices.Insert(*l.items, i, l.element(node))
+}
+
+func (l nodeList[T]) delete(i int) {
+	*l.items = slices.Delete(*l.items, i, i+1)
+}
+
+// element returns node as an element of the list, panicking if it doesn't belong in one
+func (l nodeList[T]) element(node ast.Node) T {
+	element, ok := node.(T)
+
+	if !ok {
+		panic(fmt.Sprintf("astutil: %T cannot go in %s of %T", node, l.name, l.parent))
+	}
+
+	return element
+}
+
+type application struct {
+	pre  ApplyFunc
+	post ApplyFunc
+}
+
+// apply visits node, which is held by the field name of parent, either on its own, in which case replace puts another
+// node in its place, or as the current element of a list
+func (a *application) apply(parent ast.Node, name string, node ast.Node, replace func(ast.Node), iter *iterator) {
+	a.applyIn(&Cursor{parent: parent, name: name, node: node, replace: replace, iter: iter})
+}
+
+func (a *application) applyIn(c *Cursor) {
+	if a.pre != nil && !a.pre(c) {
+		return
+	}
+
+	// the node may have been replaced or deleted by pre
+	if c.node == nil {
+		return
+	}
+
+	a.applyChildren(c.node)
+
+	if a.post != nil && c.node != nil && !a.post(c) {
+		panic(abort)
+	}
+}
+
+// applyField visits node, the field name of parent, unless it is missing
+func applyField[T ast.Node](a *application, parent ast.Node, name string, field *T) {
+	var node ast.Node = *field
+
+	if isNil(node) {
+		return
+	}
+
+	a.apply(parent, name, node, func(replacement ast.Node) {
+		value, ok := replacement.(T)
+
+		if !ok {
+			panic(fmt.Sprintf("astutil: %T cannot replace %T in %s of %T", replacement, node, name, parent))
+		}
+
+		*field = value
+	}, nil)
+}
+
+// applyList visits the elements of items, the field name of parent, allowing for elements being deleted and inserted
+// along the way
+func applyList[T ast.Node](a *application, parent ast.Node, name string, items *[]T) {
+	var l = nodeList[T]{parent: parent, name: name, items: items}
+	var iter = &iterator{}
+
+	for iter.index < l.len() {
+		iter.step = 1
+
+		if node := l.at(iter.index); !isNil(node) {
+			a.applyIn(&Cursor{parent: parent, name: name, node: node, list: l, iter: iter})
+		}
+
+		iter.index += iter.step
+	}
+}
+
+func (a *application) applyChildren(node ast.Node) {
+	switch n := node.(type) {
+	case *ast.Program:
+		applyList(a, n, "Statements", &n.Statements)
+
+	case *ast.BlockStatement:
+		applyList(a, n, "Statements", &n.Statements)
+
+	case *ast.LetStatement:
+		applyField(a, n, "Name", &n.Name)
+		applyField(a, n, "Value", &n.Value)
+
+	case *ast.ReturnStatement:
+		applyField(a, n, "ReturnValue", &n.ReturnValue)
+
+	case *ast.ExpressionStatement:
+		applyField(a, n, "Expression", &n.Expression)
+
+	case *ast.PrefixExpression:
+		applyField(a, n, "Right", &n.Right)
+
+	case *ast.InfixExpression:
+		applyField(a, n, "Left", &n.Left)
+		applyField(a, n, "Right", &n.Right)
+
+	case *ast.IfExpression:
+		applyField(a, n, "Condition", &n.Condition)
+		applyField(a, n, "Consequence", &n.Consequence)
+		applyField(a, n, "Alternative", &n.Alternative)
+
+	case *ast.FunctionLiteral:
+		applyList(a, n, "Parameters", &n.Parameters)
+		applyField(a, n, "Body", &n.Body)
+
+	case *ast.CallExpression:
+		applyField(a, n, "Function", &n.Function)
+		applyList(a, n, "Arguments", &n.Arguments)
+
+	case *ast.ArrayLiteral:
+		applyList(a, n, "Elements", &n.Elements)
+
+	case *ast.IndexExpression:
+		applyField(a, n, "Left", &n.Left)
+		applyField(a, n, "Index", &n.Index)
+
+	case *ast.HashLiteral:
+		for i := range n.Pairs {
+			applyField(a, n, "Key", &n.Pairs[i].Key)
+			applyField(a, n, "Value", &n.Pairs[i].Value)
+		}
+	}
+}
+
+// isNil reports whether node is missing, allowing for a nil pointer held in an interface
+func isNil(node ast.Node) bool {
+	switch n := node.(type) {
+	case nil:
+		return true
+	case *ast.Identifier:
+		return n == nil
+	case *ast.BlockStatement:
+		return n == nil
+	}
+
+	return false
+}
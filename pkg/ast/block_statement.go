@@ -0,0 +1,47 @@
+package ast
+
+import (
+	"bytes"
+	"monkeyInterpreter/pkg/token"
+)
+
+type BlockStatement struct {
+	// Token is the opening { of the block
+	Token      token.Token
+	Statements []Statement
+
+	// Rbrace is the closing } of the block, kept so the span of the block covers both braces
+	Rbrace token.Token
+}
+
+func (bs *BlockStatement) TokenLiteral() string {
+	return bs.Token.Literal
+}
+
+func (bs *BlockStatement) String() string {
+	var out bytes.Buffer
+
+	for _, s := range bs.Statements {
+		out.WriteString(s.String())
+	}
+
+	return out.String()
+}
+
+func (bs *BlockStatement) Pos() token.Position {
+	return bs.Token.Pos
+}
+
+func (bs *BlockStatement) End() token.Position {
+	if bs.Rbrace.End.IsValid() {
+		return bs.Rbrace.End
+	}
+
+	if len(bs.Statements) > 0 {
+		return bs.Statements[len(bs.Statements)-1].End()
+	}
+
+	return bs.Token.End
+}
+
+func (bs *BlockStatement) statementNode() {}
@@ -0,0 +1,26 @@
+package ast
+
+import "monkeyInterpreter/pkg/token"
+
+type Boolean struct {
+	Token token.Token
+	Value bool
+}
+
+func (b *Boolean) TokenLiteral() string {
+	return b.Token.Literal
+}
+
+func (b *Boolean) String() string {
+	return b.Token.Literal
+}
+
+func (b *Boolean) Pos() token.Position {
+	return b.Token.Pos
+}
+
+func (b *Boolean) End() token.Position {
+	return b.Token.End
+}
+
+func (b *Boolean) expressionNode() {}
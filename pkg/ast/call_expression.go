@@ -0,0 +1,62 @@
+package ast
+
+import (
+	"bytes"
+	"monkeyInterpreter/pkg/token"
+	"strings"
+)
+
+type CallExpression struct {
+	// Token is the ( that opens the argument list
+	Token token.Token
+
+	// Function is either an Identifier or a FunctionLiteral, or any other expression that produces a function
+	Function  Expression
+	Arguments []Expression
+
+	// Rparen is the ) that closes the argument list, kept so the span of the call covers it
+	Rparen token.Token
+}
+
+func (ce *CallExpression) TokenLiteral() string {
+	return ce.Token.Literal
+}
+
+func (ce *CallExpression) String() string {
+	var out bytes.Buffer
+
+	args := []string{}
+
+	for _, a := range ce.Arguments {
+		args = append(args, a.String())
+	}
+
+	out.WriteString(ce.Function.String())
+	out.WriteString("(")
+	out.WriteString(strings.Join(args, ", "))
+	out.WriteString(")")
+
+	return out.String()
+}
+
+func (ce *CallExpression) Pos() token.Position {
+	if ce.Function != nil {
+		return ce.Function.Pos()
+	}
+
+	return ce.Token.Pos
+}
+
+func (ce *CallExpression) End() token.Position {
+	if ce.Rparen.End.IsValid() {
+		return ce.Rparen.End
+	}
+
+	if len(ce.Arguments) > 0 {
+		return ce.Arguments[len(ce.Arguments)-1].End()
+	}
+
+	return ce.Token.End
+}
+
+func (ce *CallExpression) expressionNode() {}
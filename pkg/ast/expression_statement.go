@@ -19,4 +19,20 @@ func (es *ExpressionStatement) String() string {
 	return ""
 }
 
+func (es *ExpressionStatement) Pos() token.Position {
+	if es.Expression != nil {
+		return es.Expression.Pos()
+	}
+
+	return es.Token.Pos
+}
+
+func (es *ExpressionStatement) End() token.Position {
+	if es.Expression != nil {
+		return es.Expression.End()
+	}
+
+	return es.Token.End
+}
+
 func (es *ExpressionStatement) statementNode() {}
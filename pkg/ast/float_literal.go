@@ -0,0 +1,26 @@
+package ast
+
+import "monkeyInterpreter/pkg/token"
+
+type FloatLiteral struct {
+	Token token.Token
+	Value float64
+}
+
+func (fl *FloatLiteral) TokenLiteral() string {
+	return fl.Token.Literal
+}
+
+func (fl *FloatLiteral) String() string {
+	return fl.Token.Literal
+}
+
+func (fl *FloatLiteral) Pos() token.Position {
+	return fl.Token.Pos
+}
+
+func (fl *FloatLiteral) End() token.Position {
+	return fl.Token.End
+}
+
+func (fl *FloatLiteral) expressionNode() {}
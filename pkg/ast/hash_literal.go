@@ -0,0 +1,62 @@
+package ast
+
+import (
+	"bytes"
+	"monkeyInterpreter/pkg/token"
+	"strings"
+)
+
+// HashPair is a single key: value entry of a HashLiteral
+type HashPair struct {
+	Key   Expression
+	Value Expression
+}
+
+type HashLiteral struct {
+	// Token is the { that opens the hash
+	Token token.Token
+
+	// Pairs are kept in the order they were written, so that printing and walking the tree is predictable
+	Pairs []HashPair
+
+	// Rbrace is the } that closes the hash, kept so the span of the hash covers it
+	Rbrace token.Token
+}
+
+func (hl *HashLiteral) TokenLiteral() string {
+	return hl.Token.Literal
+}
+
+func (hl *HashLiteral) String() string {
+	var out bytes.Buffer
+
+	pairs := []string{}
+
+	for _, pair := range hl.Pairs {
+		pairs = append(pairs, pair.Key.String()+": "+pair.Value.String())
+	}
+
+	out.WriteString("{")
+	out.WriteString(strings.Join(pairs, ", "))
+	out.WriteString("}")
+
+	return out.String()
+}
+
+func (hl *HashLiteral) Pos() token.Position {
+	return hl.Token.Pos
+}
+
+func (hl *HashLiteral) End() token.Position {
+	if hl.Rbrace.End.IsValid() {
+		return hl.Rbrace.End
+	}
+
+	if len(hl.Pairs) > 0 {
+		return hl.Pairs[len(hl.Pairs)-1].Value.End()
+	}
+
+	return hl.Token.End
+}
+
+func (hl *HashLiteral) expressionNode() {}
@@ -16,3 +16,11 @@ func (i *Identifier) TokenLiteral() string {
 func (i *Identifier) String() string {
 	return i.Value
 }
+
+func (i *Identifier) Pos() token.Position {
+	return i.Token.Pos
+}
+
+func (i *Identifier) End() token.Position {
+	return i.Token.End
+}
@@ -0,0 +1,53 @@
+package ast
+
+import (
+	"bytes"
+	"monkeyInterpreter/pkg/token"
+)
+
+type IfExpression struct {
+	Token       token.Token
+	Condition   Expression
+	Consequence *BlockStatement
+
+	// Alternative is nil when there is no else branch
+	Alternative *BlockStatement
+}
+
+func (ie *IfExpression) TokenLiteral() string {
+	return ie.Token.Literal
+}
+
+func (ie *IfExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("if")
+	out.WriteString(ie.Condition.String())
+	out.WriteString(" ")
+	out.WriteString(ie.Consequence.String())
+
+	if ie.Alternative != nil {
+		out.WriteString("else ")
+		out.WriteString(ie.Alternative.String())
+	}
+
+	return out.String()
+}
+
+func (ie *IfExpression) Pos() token.Position {
+	return ie.Token.Pos
+}
+
+func (ie *IfExpression) End() token.Position {
+	if ie.Alternative != nil {
+		return ie.Alternative.End()
+	}
+
+	if ie.Consequence != nil {
+		return ie.Consequence.End()
+	}
+
+	return ie.Token.End
+}
+
+func (ie *IfExpression) expressionNode() {}
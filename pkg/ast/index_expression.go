@@ -0,0 +1,54 @@
+package ast
+
+import (
+	"bytes"
+	"monkeyInterpreter/pkg/token"
+)
+
+type IndexExpression struct {
+	// Token is the [ that opens the index
+	Token token.Token
+	Left  Expression
+	Index Expression
+
+	// Rbracket is the ] that closes the index, kept so the span of the expression covers it
+	Rbracket token.Token
+}
+
+func (ie *IndexExpression) TokenLiteral() string {
+	return ie.Token.Literal
+}
+
+func (ie *IndexExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(ie.Left.String())
+	out.WriteString("[")
+	out.WriteString(ie.Index.String())
+	out.WriteString("])")
+
+	return out.String()
+}
+
+func (ie *IndexExpression) Pos() token.Position {
+	if ie.Left != nil {
+		return ie.Left.Pos()
+	}
+
+	return ie.Token.Pos
+}
+
+func (ie *IndexExpression) End() token.Position {
+	if ie.Rbracket.End.IsValid() {
+		return ie.Rbracket.End
+	}
+
+	if ie.Index != nil {
+		return ie.Index.End()
+	}
+
+	return ie.Token.End
+}
+
+func (ie *IndexExpression) expressionNode() {}
@@ -28,4 +28,20 @@ func (ie *InfixExpression) String() string {
 	return out.String()
 }
 
+func (ie *InfixExpression) Pos() token.Position {
+	if ie.Left != nil {
+		return ie.Left.Pos()
+	}
+
+	return ie.Token.Pos
+}
+
+func (ie *InfixExpression) End() token.Position {
+	if ie.Right != nil {
+		return ie.Right.End()
+	}
+
+	return ie.Token.End
+}
+
 func (ie *InfixExpression) expressionNode() {}
@@ -15,4 +15,12 @@ func (il *IntegerLiteral) String() string {
 	return il.Token.Literal
 }
 
+func (il *IntegerLiteral) Pos() token.Position {
+	return il.Token.Pos
+}
+
+func (il *IntegerLiteral) End() token.Position {
+	return il.Token.End
+}
+
 func (il *IntegerLiteral) expressionNode() {}
@@ -0,0 +1,905 @@
+package ast
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"monkeyInterpreter/pkg/token"
+	"reflect"
+	"strings"
+)
+
+// MarshalJSON returns node in its JSON form, which UnmarshalJSON reads back into the same tree.  Every node is written
+// as an object whose "type" names its Go type, such as "InfixExpression", followed by its span as "pos" and "end" and
+// then its fields, tokens included.  The span is only written for tools that know nothing of Go, as reading the node
+// back works it out from the tokens again.  A missing node is written as null.
+func MarshalJSON(node Node) ([]byte, error) {
+	var e = encoder{spans: map[Node]span{}}
+
+	e.node(node)
+
+	if e.err != nil {
+		return nil, e.err
+	}
+
+	return e.buf.Bytes(), nil
+}
+
+// UnmarshalJSON reads back a node of any type from its JSON form
+func UnmarshalJSON(data []byte) (Node, error) {
+	var d = decoder{json.NewDecoder(bytes.NewReader(data))}
+
+	node, err := d.node()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := d.Token(); err != io.EOF {
+		if err == nil {
+			err = errors.New("ast: unexpected data after the node")
+		}
+
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// nodeTypes creates an empty node for every type name found in the JSON form
+var nodeTypes = map[string]func() Node{
+	"Program":             func() Node { return &Program{} },
+	"BlockStatement":      func() Node { return &BlockStatement{} },
+	"LetStatement":        func() Node { return &LetStatement{} },
+	"ReturnStatement":     func() Node { return &ReturnStatement{} },
+	"ExpressionStatement": func() Node { return &ExpressionStatement{} },
+	"Identifier":          func() Node { return &Identifier{} },
+	"IntegerLiteral":      func() Node { return &IntegerLiteral{} },
+	"FloatLiteral":        func() Node { return &FloatLiteral{} },
+	"StringLiteral":       func() Node { return &StringLiteral{} },
+	"Boolean":             func() Node { return &Boolean{} },
+	"PrefixExpression":    func() Node { return &PrefixExpression{} },
+	"InfixExpression":     func() Node { return &InfixExpression{} },
+	"IfExpression":        func() Node { return &IfExpression{} },
+	"FunctionLiteral":     func() Node { return &FunctionLiteral{} },
+	"CallExpression":      func() Node { return &CallExpression{} },
+	"ArrayLiteral":        func() Node { return &ArrayLiteral{} },
+	"IndexExpression":     func() Node { return &IndexExpression{} },
+	"HashLiteral":         func() Node { return &HashLiteral{} },
+}
+
+func typeName(node Node) string {
+	return strings.TrimPrefix(fmt.Sprintf("%T", node), "*ast.")
+}
+
+// kindName names the kind of node T holds, such as Expression or Identifier
+func kindName[T Node]() string {
+	var kind = reflect.TypeFor[T]()
+
+	if kind.Kind() == reflect.Pointer {
+		kind = kind.Elem()
+	}
+
+	return kind.Name()
+}
+
+// isNil reports whether node is missing, either as a nil interface or as a nil pointer held by one
+func isNil(node Node) bool {
+	if node == nil {
+		return true
+	}
+
+	var value = reflect.ValueOf(node)
+
+	return value.Kind() == reflect.Pointer && value.IsNil()
+}
+
+// encoder writes the JSON form of a tree in a single pass.  Calling MarshalJSON on every node instead would have
+// encoding/json check the output of every subtree again, and working out every span with Pos and End would walk the
+// leftmost and rightmost edges of a subtree again for every node on them, which costs quadratic time on deep trees.
+type encoder struct {
+	buf   bytes.Buffer
+	spans map[Node]span
+	err   error
+}
+
+type span struct {
+	pos token.Position
+	end token.Position
+}
+
+// span works out the span of node the same way its Pos and End methods do, but from the spans already worked out for
+// its children
+func (e *encoder) span(node Node) span {
+	if found, ok := e.spans[node]; ok {
+		return found
+	}
+
+	var s span
+
+	switch n := node.(type) {
+	case *Program:
+		s = span{e.pos(first(n.Statements), token.Position{}), e.end(last(n.Statements), token.Position{})}
+	case *BlockStatement:
+		s = span{n.Token.Pos, e.closedEnd(n.Rbrace, last(n.Statements), n.Token)}
+	case *LetStatement:
+		s = span{n.Token.Pos, e.end(n.Value, e.end(n.Name, n.Token.End))}
+	case *ReturnStatement:
+		s = span{n.Token.Pos, e.end(n.ReturnValue, n.Token.End)}
+	case *ExpressionStatement:
+		s = span{e.pos(n.Expression, n.Token.Pos), e.end(n.Expression, n.Token.End)}
+	case *PrefixExpression:
+		s = span{n.Token.Pos, e.end(n.Right, n.Token.End)}
+	case *InfixExpression:
+		s = span{e.pos(n.Left, n.Token.Pos), e.end(n.Right, n.Token.End)}
+	case *IfExpression:
+		s = span{n.Token.Pos, e.end(n.Alternative, e.end(n.Consequence, n.Token.End))}
+	case *FunctionLiteral:
+		s = span{n.Token.Pos, e.end(n.Body, n.Token.End)}
+	case *CallExpression:
+		s = span{e.pos(n.Function, n.Token.Pos), e.closedEnd(n.Rparen, last(n.Arguments), n.Token)}
+	case *ArrayLiteral:
+		s = span{n.Token.Pos, e.closedEnd(n.Rbracket, last(n.Elements), n.Token)}
+	case *IndexExpression:
+		s = span{e.pos(n.Left, n.Token.Pos), e.closedEnd(n.Rbracket, n.Index, n.Token)}
+	case *HashLiteral:
+		var value Node
+
+		if len(n.Pairs) > 0 {
+			value = n.Pairs[len(n.Pairs)-1].Value
+		}
+
+		s = span{n.Token.Pos, e.closedEnd(n.Rbrace, value, n.Token)}
+	default:
+		s = span{node.Pos(), node.End()}
+	}
+
+	e.spans[node] = s
+
+	return s
+}
+
+// pos returns where node starts, or otherwise if node is missing
+func (e *encoder) pos(node Node, otherwise token.Position) token.Position {
+	if isNil(node) {
+		return otherwise
+	}
+
+	return e.span(node).pos
+}
+
+// end returns where node ends, or otherwise if node is missing
+func (e *encoder) end(node Node, otherwise token.Position) token.Position {
+	if isNil(node) {
+		return otherwise
+	}
+
+	return e.span(node).end
+}
+
+// closedEnd returns where a node opened by open ends: after closing if it was found, else after its last part
+func (e *encoder) closedEnd(closing token.Token, last Node, open token.Token) token.Position {
+	if closing.End.IsValid() {
+		return closing.End
+	}
+
+	return e.end(last, open.End)
+}
+
+func first[T Node](nodes []T) Node {
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	return nodes[0]
+}
+
+func last[T Node](nodes []T) Node {
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	return nodes[len(nodes)-1]
+}
+
+func (e *encoder) node(node Node) {
+	if isNil(node) {
+		e.buf.WriteString("null")
+		return
+	}
+
+	var s = e.span(node)
+
+	e.buf.WriteString(`{"type":`)
+	e.value(typeName(node))
+	e.field("pos", s.pos)
+	e.field("end", s.end)
+
+	switch n := node.(type) {
+	case *Program:
+		encodeList(e, "statements", n.Statements)
+	case *BlockStatement:
+		e.field("token", n.Token)
+		encodeList(e, "statements", n.Statements)
+		e.field("rbrace", n.Rbrace)
+	case *LetStatement:
+		e.field("token", n.Token)
+		e.child("name", n.Name)
+		e.child("value", n.Value)
+	case *ReturnStatement:
+		e.field("token", n.Token)
+		e.child("returnValue", n.ReturnValue)
+	case *ExpressionStatement:
+		e.field("token", n.Token)
+		e.child("expression", n.Expression)
+	case *Identifier:
+		e.field("token", n.Token)
+		e.field("value", n.Value)
+	case *IntegerLiteral:
+		e.field("token", n.Token)
+		e.field("value", n.Value)
+	case *FloatLiteral:
+		e.field("token", n.Token)
+		e.field("value", n.Value)
+	case *StringLiteral:
+		e.field("token", n.Token)
+		e.field("value", n.Value)
+	case *Boolean:
+		e.field("token", n.Token)
+		e.field("value", n.Value)
+	case *PrefixExpression:
+		e.field("token", n.Token)
+		e.field("operator", n.Operator)
+		e.child("right", n.Right)
+	case *InfixExpression:
+		e.field("token", n.Token)
+		e.child("left", n.Left)
+		e.field("operator", n.Operator)
+		e.child("right", n.Right)
+	case *IfExpression:
+		e.field("token", n.Token)
+		e.child("condition", n.Condition)
+		e.child("consequence", n.Consequence)
+		e.child("alternative", n.Alternative)
+	case *FunctionLiteral:
+		e.field("token", n.Token)
+		encodeList(e, "parameters", n.Parameters)
+		e.child("body", n.Body)
+	case *CallExpression:
+		e.field("token", n.Token)
+		e.child("function", n.Function)
+		encodeList(e, "arguments", n.Arguments)
+		e.field("rparen", n.Rparen)
+	case *ArrayLiteral:
+		e.field("token", n.Token)
+		encodeList(e, "elements", n.Elements)
+		e.field("rbracket", n.Rbracket)
+	case *IndexExpression:
+		e.field("token", n.Token)
+		e.child("left", n.Left)
+		e.child("index", n.Index)
+		e.field("rbracket", n.Rbracket)
+	case *HashLiteral:
+		e.field("token", n.Token)
+		e.key("pairs")
+		e.pairs(n.Pairs)
+		e.field("rbrace", n.Rbrace)
+	default:
+		panic(fmt.Sprintf("ast.MarshalJSON: unexpected node type %T", node))
+	}
+
+	e.buf.WriteByte('}')
+}
+
+func (e *encoder) key(name string) {
+	e.buf.WriteByte(',')
+	e.value(name)
+	e.buf.WriteByte(':')
+}
+
+// field writes a field that holds no nodes, such as a token or an operator
+func (e *encoder) field(name string, value any) {
+	e.key(name)
+	e.value(value)
+}
+
+func (e *encoder) child(name string, node Node) {
+	e.key(name)
+	e.node(node)
+}
+
+func (e *encoder) value(value any) {
+	data, err := json.Marshal(value)
+
+	if err != nil && e.err == nil {
+		e.err = err
+	}
+
+	e.buf.Write(data)
+}
+
+func encodeList[T Node](e *encoder, name string, nodes []T) {
+	e.key(name)
+
+	if nodes == nil {
+		e.buf.WriteString("null")
+		return
+	}
+
+	e.buf.WriteByte('[')
+
+	for i, node := range nodes {
+		if i > 0 {
+			e.buf.WriteByte(',')
+		}
+
+		e.node(node)
+	}
+
+	e.buf.WriteByte(']')
+}
+
+func (e *encoder) pairs(pairs []HashPair) {
+	if pairs == nil {
+		e.buf.WriteString("null")
+		return
+	}
+
+	e.buf.WriteByte('[')
+
+	for i, pair := range pairs {
+		if i > 0 {
+			e.buf.WriteByte(',')
+		}
+
+		e.buf.WriteString(`{"key":`)
+		e.node(pair.Key)
+		e.buf.WriteString(`,"value":`)
+		e.node(pair.Value)
+		e.buf.WriteByte('}')
+	}
+
+	e.buf.WriteByte(']')
+}
+
+// decoder reads the JSON form of a tree token by token, so every node is read once, whereas reading each one with
+// json.Unmarshal would read a subtree again for every node above it
+type decoder struct {
+	*json.Decoder
+}
+
+// node reads the next node, which is nil if it was written as null.  Its fields are read as soon as its type is known,
+// which is straight away for the JSON MarshalJSON writes, as "type" comes first.
+func (d *decoder) node() (Node, error) {
+	if open, err := d.open('{'); open == nil || err != nil {
+		return nil, err
+	}
+
+	var node Node
+	var early []earlyField
+
+	for d.More() {
+		name, err := d.key()
+
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case name == "type" && node == nil:
+			if node, err = d.newNode(); err != nil {
+				return nil, err
+			}
+
+			for _, field := range early {
+				var fieldDecoder = decoder{json.NewDecoder(bytes.NewReader(field.value))}
+
+				if err := fieldDecoder.field(node, field.name); err != nil {
+					return nil, err
+				}
+			}
+
+			early = nil
+		case node == nil:
+			var value json.RawMessage
+
+			if err := d.Decode(&value); err != nil {
+				return nil, err
+			}
+
+			early = append(early, earlyField{name, value})
+		default:
+			if err := d.field(node, name); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if _, err := d.Token(); err != nil {
+		return nil, err
+	}
+
+	if node == nil {
+		return nil, errors.New("ast: node without a type")
+	}
+
+	return node, nil
+}
+
+// earlyField holds a field found ahead of "type", until the node it belongs to is known
+type earlyField struct {
+	name  string
+	value json.RawMessage
+}
+
+func (d *decoder) newNode() (Node, error) {
+	var name string
+
+	if err := d.Decode(&name); err != nil {
+		return nil, err
+	}
+
+	newNode, ok := nodeTypes[name]
+
+	if !ok {
+		return nil, fmt.Errorf("ast: unknown node type %q", name)
+	}
+
+	return newNode(), nil
+}
+
+// open reads the start of an object or array, given by delim, returning nil if null was found instead
+func (d *decoder) open(delim json.Delim) (json.Token, error) {
+	found, err := d.Token()
+
+	if err != nil || found == nil {
+		return nil, err
+	}
+
+	if found != delim {
+		return nil, fmt.Errorf("ast: expected %s, got %v", delim, found)
+	}
+
+	return found, nil
+}
+
+func (d *decoder) key() (string, error) {
+	found, err := d.Token()
+
+	if err != nil {
+		return "", err
+	}
+
+	return found.(string), nil
+}
+
+// field reads the field called name into node, skipping fields it does not know, such as the span
+func (d *decoder) field(node Node, name string) error {
+	switch n := node.(type) {
+	case *Program:
+		switch name {
+		case "statements":
+			return decodeList(d, &n.Statements)
+		}
+	case *BlockStatement:
+		switch name {
+		case "token":
+			return d.Decode(&n.Token)
+		case "statements":
+			return decodeList(d, &n.Statements)
+		case "rbrace":
+			return d.Decode(&n.Rbrace)
+		}
+	case *LetStatement:
+		switch name {
+		case "token":
+			return d.Decode(&n.Token)
+		case "name":
+			return decodeNode(d, &n.Name)
+		case "value":
+			return decodeNode(d, &n.Value)
+		}
+	case *ReturnStatement:
+		switch name {
+		case "token":
+			return d.Decode(&n.Token)
+		case "returnValue":
+			return decodeNode(d, &n.ReturnValue)
+		}
+	case *ExpressionStatement:
+		switch name {
+		case "token":
+			return d.Decode(&n.Token)
+		case "expression":
+			return decodeNode(d, &n.Expression)
+		}
+	case *Identifier:
+		switch name {
+		case "token":
+			return d.Decode(&n.Token)
+		case "value":
+			return d.Decode(&n.Value)
+		}
+	case *IntegerLiteral:
+		switch name {
+		case "token":
+			return d.Decode(&n.Token)
+		case "value":
+			return d.Decode(&n.Value)
+		}
+	case *FloatLiteral:
+		switch name {
+		case "token":
+			return d.Decode(&n.Token)
+		case "value":
+			return d.Decode(&n.Value)
+		}
+	case *StringLiteral:
+		switch name {
+		case "token":
+			return d.Decode(&n.Token)
+		case "value":
+			return d.Decode(&n.Value)
+		}
+	case *Boolean:
+		switch name {
+		case "token":
+			return d.Decode(&n.Token)
+		case "value":
+			return d.Decode(&n.Value)
+		}
+	case *PrefixExpression:
+		switch name {
+		case "token":
+			return d.Decode(&n.Token)
+		case "operator":
+			return d.Decode(&n.Operator)
+		case "right":
+			return decodeNode(d, &n.Right)
+		}
+	case *InfixExpression:
+		switch name {
+		case "token":
+			return d.Decode(&n.Token)
+		case "left":
+			return decodeNode(d, &n.Left)
+		case "operator":
+			return d.Decode(&n.Operator)
+		case "right":
+			return decodeNode(d, &n.Right)
+		}
+	case *IfExpression:
+		switch name {
+		case "token":
+			return d.Decode(&n.Token)
+		case "condition":
+			return decodeNode(d, &n.Condition)
+		case "consequence":
+			return decodeNode(d, &n.Consequence)
+		case "alternative":
+			return decodeNode(d, &n.Alternative)
+		}
+	case *FunctionLiteral:
+		switch name {
+		case "token":
+			return d.Decode(&n.Token)
+		case "parameters":
+			return decodeList(d, &n.Parameters)
+		case "body":
+			return decodeNode(d, &n.Body)
+		}
+	case *CallExpression:
+		switch name {
+		case "token":
+			return d.Decode(&n.Token)
+		case "function":
+			return decodeNode(d, &n.Function)
+		case "arguments":
+			return decodeList(d, &n.Arguments)
+		case "rparen":
+			return d.Decode(&n.Rparen)
+		}
+	case *ArrayLiteral:
+		switch name {
+		case "token":
+			return d.Decode(&n.Token)
+		case "elements":
+			return decodeList(d, &n.Elements)
+		case "rbracket":
+			return d.Decode(&n.Rbracket)
+		}
+	case *IndexExpression:
+		switch name {
+		case "token":
+			return d.Decode(&n.Token)
+		case "left":
+			return decodeNode(d, &n.Left)
+		case "index":
+			return decodeNode(d, &n.Index)
+		case "rbracket":
+			return d.Decode(&n.Rbracket)
+		}
+	case *HashLiteral:
+		switch name {
+		case "token":
+			return d.Decode(&n.Token)
+		case "pairs":
+			return d.pairs(&n.Pairs)
+		case "rbrace":
+			return d.Decode(&n.Rbrace)
+		}
+	}
+
+	var skipped json.RawMessage
+
+	return d.Decode(&skipped)
+}
+
+// decodeNode reads the next node into target, checking it is of the kind target holds.  A node written as null
+// leaves target as it is.
+func decodeNode[T Node](d *decoder, target *T) error {
+	node, err := d.node()
+
+	if node == nil || err != nil {
+		return err
+	}
+
+	typed, ok := node.(T)
+
+	if !ok {
+		return fmt.Errorf("ast: expected %s, got %s", kindName[T](), typeName(node))
+	}
+
+	*target = typed
+
+	return nil
+}
+
+func decodeList[T Node](d *decoder, target *[]T) error {
+	if open, err := d.open('['); open == nil || err != nil {
+		return err
+	}
+
+	var nodes = []T{}
+
+	for d.More() {
+		var node T
+
+		if err := decodeNode(d, &node); err != nil {
+			return err
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	*target = nodes
+
+	_, err := d.Token()
+
+	return err
+}
+
+func (d *decoder) pairs(target *[]HashPair) error {
+	if open, err := d.open('['); open == nil || err != nil {
+		return err
+	}
+
+	var pairs = []HashPair{}
+
+	for d.More() {
+		var pair HashPair
+
+		if open, err := d.open('{'); err != nil {
+			return err
+		} else if open != nil {
+			if err := d.pair(&pair); err != nil {
+				return err
+			}
+		}
+
+		pairs = append(pairs, pair)
+	}
+
+	*target = pairs
+
+	_, err := d.Token()
+
+	return err
+}
+
+// pair reads the fields of an opened pair, up to the end of it
+func (d *decoder) pair(pair *HashPair) error {
+	for d.More() {
+		name, err := d.key()
+
+		if err != nil {
+			return err
+		}
+
+		switch name {
+		case "key":
+			err = decodeNode(d, &pair.Key)
+		case "value":
+			err = decodeNode(d, &pair.Value)
+		default:
+			var skipped json.RawMessage
+			err = d.Decode(&skipped)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := d.Token()
+
+	return err
+}
+
+// unmarshalInto reads data into node, which encoding/json hands over when it reads into a node of a known type
+func unmarshalInto[T any, P interface {
+	*T
+	Node
+}](data []byte, node P) error {
+	decoded, err := UnmarshalJSON(data)
+
+	if decoded == nil || err != nil {
+		return err
+	}
+
+	typed, ok := decoded.(P)
+
+	if !ok {
+		return fmt.Errorf("ast: expected %s, got %s", kindName[P](), typeName(decoded))
+	}
+
+	*node = *typed
+
+	return nil
+}
+
+func (prog *Program) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(prog)
+}
+
+func (prog *Program) UnmarshalJSON(data []byte) error {
+	return unmarshalInto(data, prog)
+}
+
+func (bs *BlockStatement) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(bs)
+}
+
+func (bs *BlockStatement) UnmarshalJSON(data []byte) error {
+	return unmarshalInto(data, bs)
+}
+
+func (ls *LetStatement) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(ls)
+}
+
+func (ls *LetStatement) UnmarshalJSON(data []byte) error {
+	return unmarshalInto(data, ls)
+}
+
+func (rs *ReturnStatement) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(rs)
+}
+
+func (rs *ReturnStatement) UnmarshalJSON(data []byte) error {
+	return unmarshalInto(data, rs)
+}
+
+func (es *ExpressionStatement) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(es)
+}
+
+func (es *ExpressionStatement) UnmarshalJSON(data []byte) error {
+	return unmarshalInto(data, es)
+}
+
+func (i *Identifier) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(i)
+}
+
+func (i *Identifier) UnmarshalJSON(data []byte) error {
+	return unmarshalInto(data, i)
+}
+
+func (il *IntegerLiteral) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(il)
+}
+
+func (il *IntegerLiteral) UnmarshalJSON(data []byte) error {
+	return unmarshalInto(data, il)
+}
+
+func (fl *FloatLiteral) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(fl)
+}
+
+func (fl *FloatLiteral) UnmarshalJSON(data []byte) error {
+	return unmarshalInto(data, fl)
+}
+
+func (sl *StringLiteral) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(sl)
+}
+
+func (sl *StringLiteral) UnmarshalJSON(data []byte) error {
+	return unmarshalInto(data, sl)
+}
+
+func (b *Boolean) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(b)
+}
+
+func (b *Boolean) UnmarshalJSON(data []byte) error {
+	return unmarshalInto(data, b)
+}
+
+func (pe *PrefixExpression) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(pe)
+}
+
+func (pe *PrefixExpression) UnmarshalJSON(data []byte) error {
+	return unmarshalInto(data, pe)
+}
+
+func (ie *InfixExpression) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(ie)
+}
+
+func (ie *InfixExpression) UnmarshalJSON(data []byte) error {
+	return unmarshalInto(data, ie)
+}
+
+func (ie *IfExpression) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(ie)
+}
+
+func (ie *IfExpression) UnmarshalJSON(data []byte) error {
+	return unmarshalInto(data, ie)
+}
+
+func (fl *FunctionLiteral) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(fl)
+}
+
+func (fl *FunctionLiteral) UnmarshalJSON(data []byte) error {
+	return unmarshalInto(data, fl)
+}
+
+func (ce *CallExpression) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(ce)
+}
+
+func (ce *CallExpression) UnmarshalJSON(data []byte) error {
+	return unmarshalInto(data, ce)
+}
+
+func (al *ArrayLiteral) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(al)
+}
+
+func (al *ArrayLiteral) UnmarshalJSON(data []byte) error {
+	return unmarshalInto(data, al)
+}
+
+func (ie *IndexExpression) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(ie)
+}
+
+func (ie *IndexExpression) UnmarshalJSON(data []byte) error {
+	return unmarshalInto(data, ie)
+}
+
+func (hl *HashLiteral) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(hl)
+}
+
+func (hl *HashLiteral) UnmarshalJSON(data []byte) error {
+	return unmarshalInto(data, hl)
+}
@@ -17,6 +17,22 @@ func (ls *LetStatement) TokenLiteral() string {
 	return ls.Token.Literal
 }
 
+func (ls *LetStatement) Pos() token.Position {
+	return ls.Token.Pos
+}
+
+func (ls *LetStatement) End() token.Position {
+	if ls.Value != nil {
+		return ls.Value.End()
+	}
+
+	if ls.Name != nil {
+		return ls.Name.End()
+	}
+
+	return ls.Token.End
+}
+
 func (ls *LetStatement) String() string {
 	var out bytes.Buffer
 
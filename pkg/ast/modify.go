@@ -0,0 +1,115 @@
+package ast
+
+import "fmt"
+
+// ModifierFunc returns the node to put in place of node, which may be node itself
+type ModifierFunc func(node Node) Node
+
+// Modify rewrites the tree below node bottom up, replacing every node with what modifier returns for it once its
+// children have been modified, and returns what modifier returns for node itself.  The tree is changed in place.
+// Missing parts are left missing, and modifier may not return a node that doesn't fit where the old one was, such as a
+// statement in place of an expression.
+func Modify(node Node, modifier ModifierFunc) Node {
+	switch n := node.(type) {
+	case *Program:
+		modifyStatements(n.Statements, modifier, n)
+
+	case *BlockStatement:
+		modifyStatements(n.Statements, modifier, n)
+
+	case *LetStatement:
+		if n.Name != nil {
+			n.Name = modifyAs[*Identifier](n.Name, modifier, "Name", n)
+		}
+
+		n.Value = modifyExpression(n.Value, modifier, "Value", n)
+
+	case *ReturnStatement:
+		n.ReturnValue = modifyExpression(n.ReturnValue, modifier, "ReturnValue", n)
+
+	case *ExpressionStatement:
+		n.Expression = modifyExpression(n.Expression, modifier, "Expression", n)
+
+	case *PrefixExpression:
+		n.Right = modifyExpression(n.Right, modifier, "Right", n)
+
+	case *InfixExpression:
+		n.Left = modifyExpression(n.Left, modifier, "Left", n)
+		n.Right = modifyExpression(n.Right, modifier, "Right", n)
+
+	case *IfExpression:
+		n.Condition = modifyExpression(n.Condition, modifier, "Condition", n)
+
+		if n.Consequence != nil {
+			n.Consequence = modifyAs[*BlockStatement](n.Consequence, modifier, "Consequence", n)
+		}
+
+		if n.Alternative != nil {
+			n.Alternative = modifyAs[*BlockStatement](n.Alternative, modifier, "Alternative", n)
+		}
+
+	case *FunctionLiteral:
+		for i, param := range n.Parameters {
+			if param != nil {
+				n.Parameters[i] = modifyAs[*Identifier](param, modifier, "Parameters", n)
+			}
+		}
+
+		if n.Body != nil {
+			n.Body = modifyAs[*BlockStatement](n.Body, modifier, "Body", n)
+		}
+
+	case *CallExpression:
+		n.Function = modifyExpression(n.Function, modifier, "Function", n)
+
+		for i, arg := range n.Arguments {
+			n.Arguments[i] = modifyExpression(arg, modifier, "Arguments", n)
+		}
+
+	case *ArrayLiteral:
+		for i, element := range n.Elements {
+			n.Elements[i] = modifyExpression(element, modifier, "Elements", n)
+		}
+
+	case *IndexExpression:
+		n.Left = modifyExpression(n.Left, modifier, "Left", n)
+		n.Index = modifyExpression(n.Index, modifier, "Index", n)
+
+	case *HashLiteral:
+		for i, pair := range n.Pairs {
+			n.Pairs[i].Key = modifyExpression(pair.Key, modifier, "Key", n)
+			n.Pairs[i].Value = modifyExpression(pair.Value, modifier, "Value", n)
+		}
+	}
+
+	return modifier(node)
+}
+
+func modifyStatements(statements []Statement, modifier ModifierFunc, parent Node) {
+	for i, stmt := range statements {
+		if stmt != nil {
+			statements[i] = modifyAs[Statement](stmt, modifier, "Statements", parent)
+		}
+	}
+}
+
+func modifyExpression(expr Expression, modifier ModifierFunc, field string, parent Node) Expression {
+	if expr == nil {
+		return nil
+	}
+
+	return modifyAs[Expression](expr, modifier, field, parent)
+}
+
+// modifyAs modifies node, which is the field of parent with the given name, and checks the result can go back there
+func modifyAs[T Node](node T, modifier ModifierFunc, field string, parent Node) T {
+	var modified = Modify(node, modifier)
+
+	result, ok := modified.(T)
+
+	if !ok {
+		panic(fmt.Sprintf("ast.Modify: %T cannot replace %T in %s of %T", modified, node, field, parent))
+	}
+
+	return result
+}
@@ -26,4 +26,16 @@ func (pe *PrefixExpression) String() string {
 	return out.String()
 }
 
+func (pe *PrefixExpression) Pos() token.Position {
+	return pe.Token.Pos
+}
+
+func (pe *PrefixExpression) End() token.Position {
+	if pe.Right != nil {
+		return pe.Right.End()
+	}
+
+	return pe.Token.End
+}
+
 func (pe *PrefixExpression) expressionNode() {}
@@ -1,6 +1,9 @@
 package ast
 
-import "bytes"
+import (
+	"bytes"
+	"monkeyInterpreter/pkg/token"
+)
 
 type Program struct {
 	Statements []Statement
@@ -14,6 +17,22 @@ func (prog *Program) TokenLiteral() string {
 	}
 }
 
+func (prog *Program) Pos() token.Position {
+	if len(prog.Statements) > 0 {
+		return prog.Statements[0].Pos()
+	}
+
+	return token.Position{}
+}
+
+func (prog *Program) End() token.Position {
+	if len(prog.Statements) > 0 {
+		return prog.Statements[len(prog.Statements)-1].End()
+	}
+
+	return token.Position{}
+}
+
 func (prog *Program) String() string {
 	var out bytes.Buffer
 
@@ -18,6 +18,18 @@ func (rs *ReturnStatement) TokenLiteral() string {
 	return rs.Token.Literal
 }
 
+func (rs *ReturnStatement) Pos() token.Position {
+	return rs.Token.Pos
+}
+
+func (rs *ReturnStatement) End() token.Position {
+	if rs.ReturnValue != nil {
+		return rs.ReturnValue.End()
+	}
+
+	return rs.Token.End
+}
+
 func (rs *ReturnStatement) String() string {
 	var out bytes.Buffer
 
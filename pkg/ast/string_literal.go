@@ -0,0 +1,28 @@
+package ast
+
+import "monkeyInterpreter/pkg/token"
+
+type StringLiteral struct {
+	Token token.Token
+
+	// Value is the contents of the string with its escape sequences already replaced
+	Value string
+}
+
+func (sl *StringLiteral) TokenLiteral() string {
+	return sl.Token.Literal
+}
+
+func (sl *StringLiteral) String() string {
+	return sl.Token.Literal
+}
+
+func (sl *StringLiteral) Pos() token.Position {
+	return sl.Token.Pos
+}
+
+func (sl *StringLiteral) End() token.Position {
+	return sl.Token.End
+}
+
+func (sl *StringLiteral) expressionNode() {}
@@ -0,0 +1,129 @@
+package ast
+
+import "fmt"
+
+// A Visitor's Visit method is called for every node met by Walk.  If the visitor it returns is not nil, Walk visits
+// each of the children of node with it, followed by a call of Visit(nil).
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk traverses the tree below node depth first, in the order the nodes were written.  It starts by calling
+// v.Visit(node), and parts of a node that are missing, such as the value of a let statement that failed to parse, are
+// skipped.
+func Walk(v Visitor, node Node) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+
+	switch n := node.(type) {
+	case *Program:
+		walkStatements(v, n.Statements)
+
+	case *BlockStatement:
+		walkStatements(v, n.Statements)
+
+	case *LetStatement:
+		if n.Name != nil {
+			Walk(v, n.Name)
+		}
+
+		walkExpression(v, n.Value)
+
+	case *ReturnStatement:
+		walkExpression(v, n.ReturnValue)
+
+	case *ExpressionStatement:
+		walkExpression(v, n.Expression)
+
+	case *Identifier, *IntegerLiteral, *FloatLiteral, *StringLiteral, *Boolean:
+		// nothing to do
+
+	case *PrefixExpression:
+		walkExpression(v, n.Right)
+
+	case *InfixExpression:
+		walkExpression(v, n.Left)
+		walkExpression(v, n.Right)
+
+	case *IfExpression:
+		walkExpression(v, n.Condition)
+
+		if n.Consequence != nil {
+			Walk(v, n.Consequence)
+		}
+
+		if n.Alternative != nil {
+			Walk(v, n.Alternative)
+		}
+
+	case *FunctionLiteral:
+		for _, param := range n.Parameters {
+			if param != nil {
+				Walk(v, param)
+			}
+		}
+
+		if n.Body != nil {
+			Walk(v, n.Body)
+		}
+
+	case *CallExpression:
+		walkExpression(v, n.Function)
+		walkExpressions(v, n.Arguments)
+
+	case *ArrayLiteral:
+		walkExpressions(v, n.Elements)
+
+	case *IndexExpression:
+		walkExpression(v, n.Left)
+		walkExpression(v, n.Index)
+
+	case *HashLiteral:
+		for _, pair := range n.Pairs {
+			walkExpression(v, pair.Key)
+			walkExpression(v, pair.Value)
+		}
+
+	default:
+		panic(fmt.Sprintf("ast.Walk: unexpected node type %T", n))
+	}
+
+	v.Visit(nil)
+}
+
+func walkStatements(v Visitor, statements []Statement) {
+	for _, stmt := range statements {
+		if stmt != nil {
+			Walk(v, stmt)
+		}
+	}
+}
+
+func walkExpressions(v Visitor, expressions []Expression) {
+	for _, expr := range expressions {
+		walkExpression(v, expr)
+	}
+}
+
+func walkExpression(v Visitor, expr Expression) {
+	if expr != nil {
+		Walk(v, expr)
+	}
+}
+
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+
+	return nil
+}
+
+// Inspect traverses the tree below node like Walk, calling f(node) for every node and then f(nil) once its children
+// are done.  The children of a node are only visited when f returns true for it.
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
@@ -0,0 +1,264 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"monkeyInterpreter/pkg/ast"
+	"monkeyInterpreter/pkg/diagnostic"
+	"monkeyInterpreter/pkg/evaluator"
+	"monkeyInterpreter/pkg/highlight"
+	"monkeyInterpreter/pkg/lexer"
+	"monkeyInterpreter/pkg/object"
+	"monkeyInterpreter/pkg/parser"
+	"monkeyInterpreter/pkg/repl"
+	"monkeyInterpreter/pkg/token"
+	"os"
+	"os/user"
+	"strings"
+)
+
+// exit codes returned by Run
+const (
+	EXIT_OK = 0
+
+	// EXIT_FAILURE means the script was read, but it has syntax errors or failed while running
+	EXIT_FAILURE = 1
+
+	// EXIT_USAGE means the command line itself was wrong, or the script could not be read
+	EXIT_USAGE = 2
+)
+
+// STDIN is the name given in place of a file to read the script from standard input
+const STDIN = "-"
+
+const usage = `Usage: monkey <command> [arguments]
+
+Commands:
+  run <file>       evaluate a script
+  tokens <file>    print the tokens of a script
+  ast <file>       print the syntax tree of a script
+  check <file>     report syntax errors without running the script
+  highlight <file> [--format=ansi|html]
+                   print a script with its syntax coloured
+  repl [--tokens]  start an interactive session, printing tokens instead of values with --tokens
+  help             print this message
+
+Use - as the file to read the script from standard input.  With no command, monkey starts the repl.
+ANSI colours are only written to a terminal, and never when NO_COLOR is set.
+`
+
+// command runs against the contents of a script, filename being the name its positions are reported with
+type command func(env *environment, filename string, source string) int
+
+// environment holds the streams a command reads from and writes to
+type environment struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// commands holds the commands that take a single script as their argument
+var commands = map[string]command{
+	"run":    runScript,
+	"tokens": printTokens,
+	"ast":    printAst,
+	"check":  checkScript,
+}
+
+// Run executes the command line in args, which does not include the program name, and returns the code the process
+// should exit with
+func Run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	var env = &environment{stdin: stdin, stdout: stdout, stderr: stderr}
+
+	if len(args) == 0 {
+		return startRepl(env, repl.MODE_EVAL)
+	}
+
+	switch args[0] {
+	case "help", "-h", "--help":
+		fmt.Fprint(stdout, usage)
+		return EXIT_OK
+	case "repl":
+		return replCommand(env, args[1:])
+	case "highlight":
+		return highlightCommand(env, args[1:])
+	}
+
+	cmd, ok := commands[args[0]]
+
+	if !ok {
+		fmt.Fprintf(stderr, "monkey: unknown command %q\n\n%s", args[0], usage)
+		return EXIT_USAGE
+	}
+
+	if len(args) != 2 {
+		fmt.Fprintf(stderr, "monkey: %s takes exactly one file\n", args[0])
+		return EXIT_USAGE
+	}
+
+	filename, source, err := readSource(args[1], stdin)
+
+	if err != nil {
+		fmt.Fprintf(stderr, "monkey: %s\n", err)
+		return EXIT_USAGE
+	}
+
+	return cmd(env, filename, source)
+}
+
+// readSource returns the name positions in path should be reported with, and its contents
+func readSource(path string, stdin io.Reader) (string, string, error) {
+	if path == STDIN {
+		source, err := io.ReadAll(stdin)
+		return "<stdin>", string(source), err
+	}
+
+	source, err := os.ReadFile(path)
+
+	return path, string(source), err
+}
+
+// parse parses source, printing any errors to stderr.  The program is nil when there were errors.
+func parse(env *environment, filename string, source string) *ast.Program {
+	var parsr = parser.New(lexer.NewFile(filename, source))
+	var program = parsr.ParseProgram()
+
+	if len(parsr.Errors()) != 0 {
+		diagnostic.RenderAll(env.stderr, source, parsr.Errors())
+		return nil
+	}
+
+	return program
+}
+
+func runScript(env *environment, filename string, source string) int {
+	var program = parse(env, filename, source)
+
+	if program == nil {
+		return EXIT_FAILURE
+	}
+
+	var scope = object.NewEnvironment()
+	scope.SetOutput(env.stdout)
+
+	var result = evaluator.Eval(program, scope)
+
+	if errObj, ok := result.(*object.Error); ok {
+		fmt.Fprintf(env.stderr, "%s: runtime error: %s\n", filename, errObj.Message)
+		return EXIT_FAILURE
+	}
+
+	return EXIT_OK
+}
+
+// printTokens prints one token per line as position, type and literal.  Tokens are printed even when the script has
+// errors, which is usually when they are wanted the most.
+func printTokens(env *environment, filename string, source string) int {
+	var lex = lexer.NewFile(filename, source)
+
+	for tok := lex.NextToken(); ; tok = lex.NextToken() {
+		fmt.Fprintf(env.stdout, "%s\t%s\t%q\n", tok.Pos, tok.Type, tok.Literal)
+
+		if tok.Type == token.EOF {
+			break
+		}
+	}
+
+	if len(lex.Errors()) != 0 {
+		diagnostic.RenderAll(env.stderr, source, lex.Errors())
+		return EXIT_FAILURE
+	}
+
+	return EXIT_OK
+}
+
+func printAst(env *environment, filename string, source string) int {
+	var program = parse(env, filename, source)
+
+	if program == nil {
+		return EXIT_FAILURE
+	}
+
+	for _, statement := range program.Statements {
+		fmt.Fprintln(env.stdout, statement.String())
+	}
+
+	return EXIT_OK
+}
+
+func checkScript(env *environment, filename string, source string) int {
+	if parse(env, filename, source) == nil {
+		return EXIT_FAILURE
+	}
+
+	return EXIT_OK
+}
+
+// highlightCommand prints a script marked up for display.  ANSI colours fall back to the plain script when stdout is
+// not a terminal or NO_COLOR is set, while HTML is always written since it is meant for a file.
+func highlightCommand(env *environment, args []string) int {
+	var format = highlight.FORMAT_ANSI
+	var files []string
+
+	for _, arg := range args {
+		if value, ok := strings.CutPrefix(arg, "--format="); ok {
+			format = highlight.Format(value)
+		} else {
+			files = append(files, arg)
+		}
+	}
+
+	if format != highlight.FORMAT_ANSI && format != highlight.FORMAT_HTML {
+		fmt.Fprintf(env.stderr, "monkey: unknown format %q, expected ansi or html\n", format)
+		return EXIT_USAGE
+	}
+
+	if len(files) != 1 {
+		fmt.Fprintf(env.stderr, "monkey: highlight takes exactly one file\n")
+		return EXIT_USAGE
+	}
+
+	_, source, err := readSource(files[0], env.stdin)
+
+	if err != nil {
+		fmt.Fprintf(env.stderr, "monkey: %s\n", err)
+		return EXIT_USAGE
+	}
+
+	if format == highlight.FORMAT_ANSI && !highlight.ColourEnabled(env.stdout) {
+		format = highlight.FORMAT_PLAIN
+	}
+
+	fmt.Fprint(env.stdout, highlight.Source(source, format))
+
+	return EXIT_OK
+}
+
+func replCommand(env *environment, args []string) int {
+	switch {
+	case len(args) == 0:
+		return startRepl(env, repl.MODE_EVAL)
+	case len(args) == 1 && args[0] == "--tokens":
+		return startRepl(env, repl.MODE_TOKENS)
+	default:
+		fmt.Fprintf(env.stderr, "monkey: repl takes no arguments other than --tokens\n")
+		return EXIT_USAGE
+	}
+}
+
+func startRepl(env *environment, mode repl.Mode) int {
+	var name = "there"
+
+	if usr, err := user.Current(); err == nil {
+		name = usr.Username
+	}
+
+	fmt.Fprintf(env.stdout, "Hello %s! This is the Monkey programming language!\n", name)
+	fmt.Fprintf(env.stdout, "Feel free to type in commands \n")
+
+	var session = repl.New(env.stdin, env.stdout)
+	session.SetMode(mode)
+	session.Run()
+
+	return EXIT_OK
+}
@@ -0,0 +1,98 @@
+package cst
+
+import (
+	"io"
+	"iter"
+	"monkeyInterpreter/pkg/ast"
+	"monkeyInterpreter/pkg/token"
+	"strings"
+)
+
+// Node is a node of a concrete syntax tree, which unlike the ast keeps every token of the source along with the
+// whitespace and comments around it, so the tree can be written back out exactly as it was read.
+//
+// A syntax node stands for an ast.Node, and its Children are its tokens and the nodes of its parts in the order they
+// were written.  A token node is a leaf holding a single token, whose Leading, Raw and Trailing text is what gets
+// written out.  A codemod changes the source by changing the Raw text of token nodes, and everything it leaves alone
+// is written out untouched.
+type Node struct {
+	// Syntax is the ast.Node this node stands for, and is nil for a token node
+	Syntax ast.Node
+
+	// Token is the token held by a token node
+	Token token.Token
+
+	Children []*Node
+}
+
+// IsToken reports whether node is a token node rather than a syntax node
+func (node *Node) IsToken() bool {
+	return node.Syntax == nil
+}
+
+// Text returns the source of node, including the trivia of its first and last tokens
+func (node *Node) Text() string {
+	var out strings.Builder
+
+	node.WriteTo(&out)
+
+	return out.String()
+}
+
+// WriteTo writes the source of node to out, including the trivia of its first and last tokens
+func (node *Node) WriteTo(out io.Writer) (int64, error) {
+	var written int64
+
+	for leaf := range node.Tokens() {
+		for _, text := range []string{leaf.Token.Leading, leaf.Token.Raw, leaf.Token.Trailing} {
+			n, err := io.WriteString(out, text)
+			written += int64(n)
+
+			if err != nil {
+				return written, err
+			}
+		}
+	}
+
+	return written, nil
+}
+
+// Tokens returns an iterator over the token nodes under node in source order, which is node itself for a token node
+func (node *Node) Tokens() iter.Seq[*Node] {
+	return func(yield func(*Node) bool) {
+		node.yieldTokens(yield)
+	}
+}
+
+func (node *Node) yieldTokens(yield func(*Node) bool) bool {
+	if node.IsToken() {
+		return yield(node)
+	}
+
+	for _, child := range node.Children {
+		if !child.yieldTokens(yield) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Find returns the syntax node under node that stands for syntax, or nil when there isn't one
+func (node *Node) Find(syntax ast.Node) *Node {
+	if node.IsToken() {
+		return nil
+	}
+
+	if node.Syntax == syntax {
+		return node
+	}
+
+	for _, child := range node.Children {
+		if found := child.Find(syntax); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package cst
+
+import (
+	"monkeyInterpreter/pkg/ast"
+	"monkeyInterpreter/pkg/diagnostic"
+	"monkeyInterpreter/pkg/lexer"
+	"monkeyInterpreter/pkg/parser"
+	"monkeyInterpreter/pkg/token"
+)
+
+// Parse parses source into a concrete syntax tree whose root stands for the *ast.Program, returning the errors found
+// along the way.  The tree holds every byte of source even when it has errors: tokens the parser threw away are kept
+// as token nodes of the closest syntax node around them, and the EOF token holds whatever follows the last token.
+func Parse(filename string, source string) (*Node, []diagnostic.Diagnostic) {
+	var lex = lexer.NewFile(filename, source)
+
+	lex.SetMode(lexer.SCAN_TRIVIA)
+
+	var src = &recorder{lexer: lex}
+	var parsr = parser.New(src)
+	var program = parsr.ParseProgram()
+
+	// the parser gives up early when it finds too many errors, but the tree still needs the rest of the source
+	for !src.done {
+		src.NextToken()
+	}
+
+	return build(program, src.tokens), parsr.Errors()
+}
+
+// recorder hands the tokens of a lexer to the parser, keeping a copy of each one up to and including the EOF token
+type recorder struct {
+	lexer  *lexer.Lexer
+	tokens []token.Token
+	done   bool
+}
+
+func (src *recorder) NextToken() token.Token {
+	var tok = src.lexer.NextToken()
+
+	if !src.done {
+		src.tokens = append(src.tokens, tok)
+		src.done = tok.Type == token.EOF
+	}
+
+	return tok
+}
+
+func (src *recorder) Errors() []diagnostic.Diagnostic {
+	return src.lexer.Errors()
+}
+
+// build returns the node for syntax, given the tokens it was parsed from in source order.  Each part of syntax takes
+// the tokens that fall within its span, and the tokens between the parts belong to syntax itself.
+func build(syntax ast.Node, tokens []token.Token) *Node {
+	var node = &Node{Syntax: syntax}
+	var next = 0
+
+	for _, child := range children(syntax) {
+		var first = next
+
+		for first < len(tokens) && tokens[first].Pos.Offset < child.Pos().Offset {
+			first += 1
+		}
+
+		var last = first
+
+		for last < len(tokens) && tokens[last].Pos.Offset < child.End().Offset {
+			last += 1
+		}
+
+		// a part without tokens of its own, such as one built by hand, has nothing to show in the tree
+		if last == first {
+			continue
+		}
+
+		node.Children = appendTokens(node.Children, tokens[next:first])
+		node.Children = append(node.Children, build(child, tokens[first:last]))
+		next = last
+	}
+
+	node.Children = appendTokens(node.Children, tokens[next:])
+
+	return node
+}
+
+func appendTokens(nodes []*Node, tokens []token.Token) []*Node {
+	for _, tok := range tokens {
+		nodes = append(nodes, &Node{Token: tok})
+	}
+
+	return nodes
+}
+
+// children returns the parts of syntax that are nodes themselves, in the order they were written
+func children(syntax ast.Node) []ast.Node {
+	var nodes []ast.Node
+
+	ast.Inspect(syntax, func(node ast.Node) bool {
+		if node == syntax {
+			return true
+		}
+
+		if node != nil {
+			nodes = append(nodes, node)
+		}
+
+		return false
+	})
+
+	return nodes
+}
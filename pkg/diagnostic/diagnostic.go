@@ -0,0 +1,90 @@
+package diagnostic
+
+import (
+	"fmt"
+	"monkeyInterpreter/pkg/token"
+)
+
+type Severity int
+
+const (
+	ERROR Severity = iota
+	WARNING
+	NOTE
+)
+
+func (s Severity) String() string {
+	switch s {
+	case ERROR:
+		return "error"
+	case WARNING:
+		return "warning"
+	case NOTE:
+		return "note"
+	default:
+		return fmt.Sprintf("severity(%d)", int(s))
+	}
+}
+
+// Codes identify the kind of problem a Diagnostic reports, so tools can match on them without parsing the message
+const (
+	UNEXPECTED_TOKEN    = "P001"
+	EXPECTED_EXPRESSION = "P002"
+	INVALID_INTEGER     = "P003"
+	TOO_MANY_ERRORS     = "P004"
+	UNEXPECTED_EOF      = "P005"
+	INVALID_FLOAT       = "P006"
+
+	UNTERMINATED_STRING  = "L001"
+	INVALID_ESCAPE       = "L002"
+	UNTERMINATED_COMMENT = "L003"
+	INVALID_UTF8         = "L004"
+	INVALID_NUMBER       = "L005"
+	READ_FAILED          = "L006"
+)
+
+// Diagnostic describes a single problem found in the source, along with the span of source that caused it
+type Diagnostic struct {
+	Severity Severity
+	Code     string
+	Message  string
+
+	// Pos and End delimit the offending source, End being the position immediately after it
+	Pos token.Position
+	End token.Position
+
+	// Expected and Got are only set when the problem is a token other than the one the parser was looking for
+	Expected token.TokenType
+	Got      token.TokenType
+}
+
+func New(severity Severity, code string, tok token.Token, format string, a ...interface{}) Diagnostic {
+	return Diagnostic{
+		Severity: severity,
+		Code:     code,
+		Message:  fmt.Sprintf(format, a...),
+		Pos:      tok.Pos,
+		End:      tok.End,
+	}
+}
+
+// Error formats the diagnostic on a single line as file:line:column: severity[code]: message
+func (d Diagnostic) Error() string {
+	var out = d.Severity.String()
+
+	if d.Code != "" {
+		out += "[" + d.Code + "]"
+	}
+
+	out += ": " + d.Message
+
+	if d.Pos.IsValid() || d.Pos.Filename != "" {
+		out = d.Pos.String() + ": " + out
+	}
+
+	return out
+}
+
+func (d Diagnostic) String() string {
+	return d.Error()
+}
@@ -0,0 +1,78 @@
+package diagnostic
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Render writes d to out followed by the source line it points at, with the offending span underlined:
+//
+//	script.mk:3:9: error[P002]: expected an expression, got ) instead
+//	   3 | let x = );
+//	     |         ^
+//
+// source must be the complete input the diagnostic was produced from.  When the position of d is unknown, or does not
+// fall inside source, only the message is written.
+func Render(out io.Writer, source string, d Diagnostic) {
+	fmt.Fprintln(out, d.Error())
+
+	if !d.Pos.IsValid() || d.Pos.Offset > len(source) {
+		return
+	}
+
+	var lineStart = strings.LastIndexByte(source[:d.Pos.Offset], '\n') + 1
+	var lineEnd = len(source)
+
+	if i := strings.IndexByte(source[lineStart:], '\n'); i >= 0 {
+		lineEnd = lineStart + i
+	}
+
+	var line = strings.TrimRight(source[lineStart:lineEnd], "\r")
+	var gutter = fmt.Sprintf("%4d", d.Pos.Line)
+
+	fmt.Fprintf(out, "%s | %s\n", gutter, line)
+	fmt.Fprintf(out, "%s | %s\n", strings.Repeat(" ", len(gutter)), underline(line, d.Pos.Offset-lineStart, d.End.Offset-lineStart))
+}
+
+// RenderAll renders every diagnostic in diagnostics, one after the other
+func RenderAll(out io.Writer, source string, diagnostics []Diagnostic) {
+	for _, d := range diagnostics {
+		Render(out, source, d)
+	}
+}
+
+// underline builds the caret line for the span [start, end) of line, given as byte offsets.  Tabs before the span are
+// copied so the carets stay aligned with the source no matter how wide the terminal renders a tab, and every other
+// character before the span becomes a single space however many bytes it takes.
+func underline(line string, start int, end int) string {
+	if start > len(line) {
+		start = len(line)
+	}
+
+	// spans that continue onto the next line are cut off at the end of this one
+	if end > len(line) {
+		end = len(line)
+	}
+
+	var out strings.Builder
+
+	for _, ch := range line[:start] {
+		if ch == '\t' {
+			out.WriteByte('\t')
+		} else {
+			out.WriteByte(' ')
+		}
+	}
+
+	var width = 0
+
+	if end > start {
+		width = utf8.RuneCountInString(line[start:end])
+	}
+
+	out.WriteString(strings.Repeat("^", max(width, 1)))
+
+	return out.String()
+}
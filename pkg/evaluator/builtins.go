@@ -0,0 +1,126 @@
+package evaluator
+
+import (
+	"fmt"
+	"monkeyInterpreter/pkg/object"
+)
+
+// builtins are the functions available to every program.  A let statement binding the same name shadows them.
+var builtins = map[string]*object.Builtin{
+	"len": {Fn: func(env *object.Environment, args ...object.Object) object.Object {
+		if len(args) != 1 {
+			return newError("wrong number of arguments: expected 1, got %d", len(args))
+		}
+
+		switch arg := args[0].(type) {
+		case *object.String:
+			return &object.Integer{Value: int64(len(arg.Value))}
+		case *object.Array:
+			return &object.Integer{Value: int64(len(arg.Elements))}
+		case *object.Hash:
+			return &object.Integer{Value: int64(len(arg.Pairs))}
+		default:
+			return newError("argument to `len` not supported, got %s", args[0].Type())
+		}
+	}},
+
+	"first": {Fn: func(env *object.Environment, args ...object.Object) object.Object {
+		arr, err := arrayArgument("first", args)
+
+		if err != nil {
+			return err
+		}
+
+		if len(arr.Elements) > 0 {
+			return arr.Elements[0]
+		}
+
+		return NULL
+	}},
+
+	"last": {Fn: func(env *object.Environment, args ...object.Object) object.Object {
+		arr, err := arrayArgument("last", args)
+
+		if err != nil {
+			return err
+		}
+
+		if length := len(arr.Elements); length > 0 {
+			return arr.Elements[length-1]
+		}
+
+		return NULL
+	}},
+
+	// rest returns a new array holding every element but the first
+	"rest": {Fn: func(env *object.Environment, args ...object.Object) object.Object {
+		arr, err := arrayArgument("rest", args)
+
+		if err != nil {
+			return err
+		}
+
+		if length := len(arr.Elements); length > 0 {
+			newElements := make([]object.Object, length-1)
+			copy(newElements, arr.Elements[1:length])
+
+			return &object.Array{Elements: newElements}
+		}
+
+		return NULL
+	}},
+
+	// push returns a new array with its second argument added to the end, the array passed in is left unchanged
+	"push": {Fn: func(env *object.Environment, args ...object.Object) object.Object {
+		if len(args) != 2 {
+			return newError("wrong number of arguments: expected 2, got %d", len(args))
+		}
+
+		arr, ok := args[0].(*object.Array)
+
+		if !ok {
+			return newError("argument to `push` must be ARRAY, got %s", args[0].Type())
+		}
+
+		length := len(arr.Elements)
+		newElements := make([]object.Object, length+1)
+		copy(newElements, arr.Elements)
+		newElements[length] = args[1]
+
+		return &object.Array{Elements: newElements}
+	}},
+
+	"puts": {Fn: func(env *object.Environment, args ...object.Object) object.Object {
+		for _, arg := range args {
+			fmt.Fprintln(env.Output(), arg.Inspect())
+		}
+
+		return NULL
+	}},
+}
+
+// BuiltinNames returns the names of every builtin function
+func BuiltinNames() []string {
+	names := make([]string, 0, len(builtins))
+
+	for name := range builtins {
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// arrayArgument checks that a builtin taking a single array was called with one
+func arrayArgument(name string, args []object.Object) (*object.Array, *object.Error) {
+	if len(args) != 1 {
+		return nil, newError("wrong number of arguments: expected 1, got %d", len(args))
+	}
+
+	arr, ok := args[0].(*object.Array)
+
+	if !ok {
+		return nil, newError("argument to `%s` must be ARRAY, got %s", name, args[0].Type())
+	}
+
+	return arr, nil
+}
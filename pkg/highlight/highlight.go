@@ -0,0 +1,161 @@
+package highlight
+
+import (
+	"html"
+	"io"
+	"monkeyInterpreter/pkg/lexer"
+	"monkeyInterpreter/pkg/lineedit"
+	"monkeyInterpreter/pkg/token"
+	"os"
+	"strings"
+)
+
+// Class groups the token types that are coloured the same way
+type Class string
+
+const (
+	CLASS_NONE       Class = ""
+	CLASS_KEYWORD    Class = "keyword"
+	CLASS_IDENTIFIER Class = "identifier"
+	CLASS_NUMBER     Class = "number"
+	CLASS_STRING     Class = "string"
+	CLASS_OPERATOR   Class = "operator"
+	CLASS_COMMENT    Class = "comment"
+	CLASS_ILLEGAL    Class = "illegal"
+)
+
+// Format is the kind of markup the highlighted source is written in
+type Format string
+
+const (
+	// FORMAT_ANSI colours the source with terminal escape sequences
+	FORMAT_ANSI Format = "ansi"
+
+	// FORMAT_HTML wraps the source in a pre element, with a span for every token carrying a class named after its
+	// Class, so the colours can be chosen by a style sheet
+	FORMAT_HTML Format = "html"
+
+	// FORMAT_PLAIN leaves the source as it is
+	FORMAT_PLAIN Format = "plain"
+)
+
+// ansiColours holds the escape sequence that starts each class.  Delimiters are left in the terminal's own colour, as
+// colouring every bracket and comma would only add noise.
+var ansiColours = map[Class]string{
+	CLASS_KEYWORD:    "\x1b[35m",
+	CLASS_IDENTIFIER: "\x1b[34m",
+	CLASS_NUMBER:     "\x1b[36m",
+	CLASS_STRING:     "\x1b[32m",
+	CLASS_OPERATOR:   "\x1b[33m",
+	CLASS_COMMENT:    "\x1b[90m",
+	CLASS_ILLEGAL:    "\x1b[4;31m",
+}
+
+const ansiReset = "\x1b[0m"
+
+// HTML_CLASS_PREFIX is put in front of the Class in the class attribute of every span in FORMAT_HTML
+const HTML_CLASS_PREFIX = "mk-"
+
+// ClassOf returns the class tokens of type tokenType are coloured as, which is CLASS_NONE for delimiters
+func ClassOf(tokenType token.TokenType) Class {
+	switch tokenType {
+	case token.FUNCTION, token.LET, token.IF, token.ELSE, token.RETURN, token.TRUE, token.FALSE:
+		return CLASS_KEYWORD
+	case token.IDENT:
+		return CLASS_IDENTIFIER
+	case token.INT, token.FLOAT:
+		return CLASS_NUMBER
+	case token.STRING:
+		return CLASS_STRING
+	case token.ASSIGN, token.PLUS, token.MINUS, token.ASTERISK, token.SLASH, token.PERCENT, token.BANG,
+		token.LESSTHAN, token.GREATERTHAN, token.LT_EQ, token.GT_EQ, token.EQ, token.NOT_EQ, token.AND, token.OR:
+		return CLASS_OPERATOR
+	case token.COMMENT, token.DOC_COMMENT:
+		return CLASS_COMMENT
+	case token.ILLEGAL:
+		return CLASS_ILLEGAL
+	default:
+		return CLASS_NONE
+	}
+}
+
+// Source returns source marked up in format.  The text between tokens, and anything after the lexer stops, is copied
+// unchanged, so removing the markup always gives back the original source.
+func Source(source string, format Format) string {
+	var out strings.Builder
+	var lex = lexer.New(source)
+	var offset = 0
+
+	lex.SetMode(lexer.SCAN_COMMENTS)
+
+	if format == FORMAT_HTML {
+		out.WriteString(`<pre class="` + HTML_CLASS_PREFIX + `source">`)
+	}
+
+	var next = lex.NextToken()
+
+	for next.Type != token.EOF {
+		var tok = next
+		next = lex.NextToken()
+
+		// a run of illegal characters, such as the bytes of a broken UTF-8 sequence, is marked up as a single span
+		// rather than one for every byte
+		for tok.Type == token.ILLEGAL && next.Type == token.ILLEGAL && next.Pos.Offset == tok.End.Offset {
+			tok.End = next.End
+			next = lex.NextToken()
+		}
+
+		writeText(&out, format, source[offset:tok.Pos.Offset], CLASS_NONE)
+		writeText(&out, format, source[tok.Pos.Offset:tok.End.Offset], ClassOf(tok.Type))
+		offset = tok.End.Offset
+	}
+
+	writeText(&out, format, source[offset:], CLASS_NONE)
+
+	if format == FORMAT_HTML {
+		out.WriteString("</pre>\n")
+	}
+
+	return out.String()
+}
+
+// ANSI returns line coloured for a terminal.  It suits lineedit.Editor.Highlight.
+func ANSI(line string) string {
+	return Source(line, FORMAT_ANSI)
+}
+
+func writeText(out *strings.Builder, format Format, text string, class Class) {
+	if text == "" {
+		return
+	}
+
+	switch format {
+	case FORMAT_ANSI:
+		if colour, ok := ansiColours[class]; ok {
+			out.WriteString(colour + text + ansiReset)
+			return
+		}
+	case FORMAT_HTML:
+		if class != CLASS_NONE {
+			out.WriteString(`<span class="` + HTML_CLASS_PREFIX + string(class) + `">` + html.EscapeString(text) + "</span>")
+		} else {
+			out.WriteString(html.EscapeString(text))
+		}
+
+		return
+	}
+
+	out.WriteString(text)
+}
+
+// ColourEnabled reports whether colours should be written to out.  They are only written to a terminal, and never
+// when the NO_COLOR environment variable is set to anything, following https://no-color.org.
+func ColourEnabled(out io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	file, ok := out.(*os.File)
+
+	return ok && lineedit.IsTerminal(int(file.Fd()))
+}
@@ -0,0 +1,102 @@
+package lexer
+
+import (
+	"monkeyInterpreter/pkg/diagnostic"
+	"monkeyInterpreter/pkg/token"
+)
+
+// Mode controls what the lexer produces besides the tokens the parser needs
+type Mode uint
+
+const (
+	// SCAN_COMMENTS returns comments as COMMENT and DOC_COMMENT tokens instead of skipping them like whitespace
+	SCAN_COMMENTS Mode = 1 << iota
+
+	// SCAN_TRIVIA attaches the whitespace and comments around every token to it as trivia, along with the raw text of
+	// the token, so that the source can be rebuilt byte for byte.  Comments are always trivia in this mode, even
+	// alongside SCAN_COMMENTS.
+	SCAN_TRIVIA
+)
+
+// SetMode changes what the lexer produces from the next token onwards
+func (lexer *Lexer) SetMode(mode Mode) {
+	lexer.mode = mode
+}
+
+// scansComments reports whether comments are returned as tokens rather than skipped
+func (lexer *Lexer) scansComments() bool {
+	return lexer.mode&SCAN_COMMENTS != 0 && lexer.mode&SCAN_TRIVIA == 0
+}
+
+// atComment reports whether a comment starts at the current character
+func (lexer *Lexer) atComment() bool {
+	return lexer.ch == '/' && (lexer.peakChar() == '/' || lexer.peakChar() == '*')
+}
+
+// skipWhitespaceAndComments skips everything up to the next token, which includes comments unless they are being
+// scanned as tokens themselves
+func (lexer *Lexer) skipWhitespaceAndComments() {
+	lexer.skipWhitespace()
+
+	for !lexer.scansComments() && lexer.atComment() {
+		lexer.readComment(lexer.currentPosition())
+		lexer.skipWhitespace()
+	}
+}
+
+// readComment reads the comment starting at the current /, returning it with its delimiters as the literal.  A line
+// comment runs up to but not including the end of the line.  Block comments nest, so a block can be commented out
+// even if it already holds comments.
+//
+// Comments starting /// or /** are documentation, and returned as DOC_COMMENT tokens.  /**/ is an ordinary empty
+// comment, and //// or /*** start ordinary comments, as they are usually rulers.
+func (lexer *Lexer) readComment(start token.Position) token.Token {
+	var tokenType token.TokenType = token.COMMENT
+
+	// step past the /, leaving the current character as the / or * saying which kind of comment this is
+	lexer.readChar()
+
+	if lexer.ch == '/' {
+		lexer.readChar()
+
+		if lexer.ch == '/' && lexer.peakChar() != '/' {
+			tokenType = token.DOC_COMMENT
+		}
+
+		for lexer.ch != '\n' && !lexer.eof {
+			lexer.readChar()
+		}
+	} else {
+		lexer.readChar()
+
+		if lexer.ch == '*' && lexer.peakChar() != '*' && lexer.peakChar() != '/' {
+			tokenType = token.DOC_COMMENT
+		}
+
+		lexer.readBlockComment(start)
+	}
+
+	return token.Token{Type: tokenType, Literal: lexer.src.slice(start.Offset, lexer.position)}
+}
+
+// readBlockComment reads the rest of a block comment once its opening /* has been read, up to and including the */
+// that closes it
+func (lexer *Lexer) readBlockComment(start token.Position) {
+	var depth = 1
+
+	for depth > 0 {
+		switch {
+		case lexer.eof:
+			lexer.addError(diagnostic.UNTERMINATED_COMMENT, start, lexer.currentPosition(), "block comment not terminated")
+			return
+		case lexer.ch == '/' && lexer.peakChar() == '*':
+			depth += 1
+			lexer.readChar()
+		case lexer.ch == '*' && lexer.peakChar() == '/':
+			depth -= 1
+			lexer.readChar()
+		}
+
+		lexer.readChar()
+	}
+}
@@ -1,13 +1,23 @@
 package lexer
 
 import (
+	"io"
+	"monkeyInterpreter/pkg/diagnostic"
 	"monkeyInterpreter/pkg/token"
+	"unicode"
+	"unicode/utf8"
 )
 
+// byteOrderMark may start the input, and is ignored when it does
+const byteOrderMark = '\uFEFF'
+
 type Lexer struct {
 
-	// input represents the source code that the Lexer will parse into tokens.
-	input string
+	// src holds the source code that the Lexer will split into tokens, or as much of it as is still needed
+	src *source
+
+	// eof is set once the end of the input has been reached
+	eof bool
 
 	// position tracks the current reading position in the input string.
 	position int
@@ -15,47 +25,129 @@ type Lexer struct {
 	// readPosition tracks the next read position in the input string
 	readPosition int
 
-	// ch is the current character to process
-	ch byte
+	// ch is the current character to process, decoded from the UTF-8 input.  An invalid byte is read as
+	// utf8.RuneError on its own.
+	ch rune
+
+	// filename is reported in the position of every token, and may be empty
+	filename string
+
+	// line and column are the 1-based location of ch
+	line   int
+	column int
+
+	// errors are the problems found in the input so far, such as strings that are never closed
+	errors []diagnostic.Diagnostic
+
+	// mode decides whether comments are skipped or returned as tokens, and whether tokens carry their trivia
+	mode Mode
+
+	// triviaStart is the offset just after the trailing trivia of the last token, where the leading trivia of the
+	// next one begins
+	triviaStart int
 }
 
 func New(input string) *Lexer {
-	var lexer = &Lexer{input: input}
+	return NewFile("", input)
+}
+
+// NewFile creates a Lexer whose token positions report filename as their source
+func NewFile(filename string, input string) *Lexer {
+	return newLexer(filename, newStringSource(input))
+}
+
+// NewReader creates a Lexer that reads its input from reader as it goes, rather than needing all of it up front.  Only
+// the token being read is kept in memory, so the input can be far larger than the memory available.  A failure to
+// read is reported as an error, and ends the input.
+func NewReader(reader io.Reader) *Lexer {
+	return NewFileReader("", reader)
+}
+
+// NewFileReader creates a Lexer like NewReader whose token positions report filename as their source
+func NewFileReader(filename string, reader io.Reader) *Lexer {
+	return newLexer(filename, newReaderSource(reader))
+}
+
+func newLexer(filename string, src *source) *Lexer {
+	var lexer = &Lexer{src: src, filename: filename, line: 1, errors: []diagnostic.Diagnostic{}}
 
 	lexer.readChar()
 
+	// editors on some platforms start UTF-8 files with a byte order mark, which is skipped without counting as a column
+	if lexer.ch == byteOrderMark {
+		lexer.readChar()
+		lexer.column = 1
+	}
+
 	return lexer
 }
 
+// Errors returns the problems found in the tokens read so far, in the order they were found
+func (lexer *Lexer) Errors() []diagnostic.Diagnostic {
+	return lexer.errors
+}
+
+// readChar moves on to the next character.  Columns count characters rather than bytes, so they match what an editor
+// shows, while positions and offsets remain byte indexes into the input.
 func (lexer *Lexer) readChar() {
-	if lexer.readPosition >= len(lexer.input) {
-		// set current character to ASCII code 0 (NUL) when we are at the limit of the input length
+	// the line and column stop moving once we have stepped past the end of the input
+	if !lexer.eof {
+		if lexer.ch == '\n' {
+			lexer.line += 1
+			lexer.column = 0
+		}
+
+		lexer.column += 1
+	}
+
+	ch, width, ok := lexer.src.runeAt(lexer.readPosition)
+
+	if !ok {
+		// set current character to ASCII code 0 (NUL) when we are at the limit of the input length, leaving the
+		// position at the end of the input however many more times we are asked to read
 		lexer.ch = 0
-	} else {
-		lexer.ch = lexer.input[lexer.readPosition]
+		lexer.position = lexer.readPosition
+		lexer.reachedEOF()
+		return
 	}
 
+	lexer.ch = ch
 	lexer.position = lexer.readPosition
-	lexer.readPosition += 1
+	lexer.readPosition += width
+
+	if lexer.ch == utf8.RuneError && width == 1 {
+		var pos = lexer.currentPosition()
+		var end = pos
+
+		end.Offset, end.Column = lexer.readPosition, pos.Column+1
+
+		lexer.addError(diagnostic.INVALID_UTF8, pos, end, "invalid UTF-8 encoding")
+	}
 }
 
 func (lexer *Lexer) NextToken() token.Token {
+	if lexer.mode&SCAN_TRIVIA != 0 {
+		return lexer.readTokenWithTrivia()
+	}
+
+	var tok = lexer.readToken()
+
+	// should the mode change, the trivia of the next token starts here
+	lexer.triviaStart = lexer.position
+
+	return tok
+}
+
+func (lexer *Lexer) readToken() token.Token {
 	var tok token.Token
 
-	lexer.skipWhitespace()
+	lexer.skipWhitespaceAndComments()
+
+	var start = lexer.currentPosition()
 
 	switch lexer.ch {
 	case '=':
-		if lexer.peakChar() == '=' {
-			var ch = lexer.ch
-
-			// read next char in input
-			lexer.readChar()
-			var literal = string(ch) + string(lexer.ch)
-			tok = token.Token{Type: token.EQ, Literal: literal}
-		} else {
-			tok = token.NewToken(token.ASSIGN, lexer.ch)
-		}
+		tok = lexer.readOperator('=', token.EQ, token.ASSIGN)
 	case ';':
 		tok = token.NewToken(token.SEMICOLON, lexer.ch)
 	case '(':
@@ -71,52 +163,117 @@ func (lexer *Lexer) NextToken() token.Token {
 	case '*':
 		tok = token.NewToken(token.ASTERISK, lexer.ch)
 	case '/':
+		if lexer.atComment() {
+			tok = lexer.readComment(start)
+			tok.Pos, tok.End = start, lexer.currentPosition()
+			return tok
+		}
+
 		tok = token.NewToken(token.SLASH, lexer.ch)
 	case '!':
-		if lexer.peakChar() == '=' {
-			var ch = lexer.ch
-
-			// read next char in input
-			lexer.readChar()
-			var literal = string(ch) + string(lexer.ch)
-			tok = token.Token{Type: token.NOT_EQ, Literal: literal}
-		} else {
-			tok = token.NewToken(token.BANG, lexer.ch)
-		}
+		tok = lexer.readOperator('=', token.NOT_EQ, token.BANG)
 	case '>':
-		tok = token.NewToken(token.GREATERTHAN, lexer.ch)
+		tok = lexer.readOperator('=', token.GT_EQ, token.GREATERTHAN)
 	case '<':
-		tok = token.NewToken(token.LESSTHAN, lexer.ch)
+		tok = lexer.readOperator('=', token.LT_EQ, token.LESSTHAN)
+	case '%':
+		tok = token.NewToken(token.PERCENT, lexer.ch)
+	case '&':
+		// there are no bitwise operators, so a single & or | is illegal
+		tok = lexer.readOperator('&', token.AND, token.ILLEGAL)
+	case '|':
+		tok = lexer.readOperator('|', token.OR, token.ILLEGAL)
 	case '{':
 		tok = token.NewToken(token.LBRACE, lexer.ch)
 	case '}':
 		tok = token.NewToken(token.RBRACE, lexer.ch)
+	case '[':
+		tok = token.NewToken(token.LBRACKET, lexer.ch)
+	case ']':
+		tok = token.NewToken(token.RBRACKET, lexer.ch)
+	case ':':
+		tok = token.NewToken(token.COLON, lexer.ch)
+	case '"':
+		tok = lexer.readString(start)
+		tok.Pos, tok.End = start, lexer.currentPosition()
+		return tok
 	case 0:
+		// a NUL byte in the input is not the end of it
+		if !lexer.eof {
+			tok = token.Token{Type: token.ILLEGAL, Literal: "\x00"}
+			break
+		}
+
 		tok.Literal = ""
 		tok.Type = token.EOF
+		tok.Pos, tok.End = start, start
+		return tok
 	default:
 		if isLetter(lexer.ch) {
 			tok.Literal = lexer.readIdentifier()
 			tok.Type = token.LookUpIdent(tok.Literal)
+			tok.Pos, tok.End = start, lexer.currentPosition()
 			return tok
 		} else if isInteger(lexer.ch) {
-			tok.Type = token.INT
-			tok.Literal = lexer.readNumber()
+			tok = lexer.readNumber(start)
+			tok.Pos, tok.End = start, lexer.currentPosition()
 			return tok
 		} else {
-			tok = token.NewToken(token.ILLEGAL, lexer.ch)
+			// the literal is taken from the input so that an invalid byte is kept as it was, rather than as the
+			// replacement character it was decoded to
+			tok = token.Token{Type: token.ILLEGAL, Literal: lexer.src.slice(lexer.position, lexer.readPosition)}
 		}
 	}
 
 	lexer.readChar()
 
+	tok.Pos, tok.End = start, lexer.currentPosition()
+
 	return tok
 }
 
+// readOperator returns a twoChar token when the character after the current one is second, and a oneChar token for the
+// current character on its own otherwise.  The current character is left on the last character of the token.
+func (lexer *Lexer) readOperator(second rune, twoChar token.TokenType, oneChar token.TokenType) token.Token {
+	if lexer.peakChar() != second {
+		return token.NewToken(oneChar, lexer.ch)
+	}
+
+	var ch = lexer.ch
+
+	// read next char in input
+	lexer.readChar()
+
+	return token.Token{Type: twoChar, Literal: string(ch) + string(lexer.ch)}
+}
+
+func (lexer *Lexer) currentPosition() token.Position {
+	return token.Position{Filename: lexer.filename, Line: lexer.line, Column: lexer.column, Offset: lexer.position}
+}
+
+// reachedEOF records that the input has run out, reporting why if the reader failed rather than simply ending
+func (lexer *Lexer) reachedEOF() {
+	if lexer.eof {
+		return
+	}
+
+	lexer.eof = true
+
+	if err := lexer.src.err; err != io.EOF {
+		var pos = lexer.currentPosition()
+		lexer.addError(diagnostic.READ_FAILED, pos, pos, "could not read the rest of the input: %s", err)
+	}
+}
+
 func (lexer *Lexer) skipWhitespace() {
 	for lexer.ch == ' ' || lexer.ch == '\t' || lexer.ch == '\n' || lexer.ch == '\r' {
 		lexer.readChar()
 	}
+
+	// nothing before the token about to be read will be needed again, unless it is kept as trivia
+	if lexer.mode&SCAN_TRIVIA == 0 {
+		lexer.src.release(lexer.position)
+	}
 }
 
 func (lexer *Lexer) readIdentifier() string {
@@ -125,36 +282,21 @@ func (lexer *Lexer) readIdentifier() string {
 		lexer.readChar()
 	}
 
-	return lexer.input[start:lexer.position]
+	return lexer.src.slice(start, lexer.position)
 }
 
-func (lexer *Lexer) readNumber() string {
-	var start = lexer.position
-
-	for isInteger(lexer.ch) {
-		lexer.readChar()
-	}
+func (lexer *Lexer) peakChar() rune {
+	var ch, _, _ = lexer.src.runeAt(lexer.readPosition)
 
-	return lexer.input[start:lexer.position]
+	return ch
 }
 
-func (lexer *Lexer) peakChar() byte {
-	if lexer.readPosition >= len(lexer.input) {
-		return 0
-	} else {
-		return lexer.input[lexer.readPosition]
-	}
-}
-
-func isLetter(ch byte) bool {
-	var isLowerCase = 'a' <= ch && ch <= 'z'
-	var isUpperCase = 'A' <= ch && ch <= 'Z'
-	var isUnderScore = ch == '_'
-
-	return isLowerCase || isUpperCase || isUnderScore
+// isLetter accepts letters from any script, so identifiers can be written in the language of the people writing them
+func isLetter(ch rune) bool {
+	return unicode.IsLetter(ch) || ch == '_'
 }
 
 // only handling basic integer types to simplify things
-func isInteger(ch byte) bool {
+func isInteger(ch rune) bool {
 	return '0' <= ch && ch <= '9'
 }
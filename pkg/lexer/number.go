@@ -0,0 +1,167 @@
+package lexer
+
+import (
+	"monkeyInterpreter/pkg/diagnostic"
+	"monkeyInterpreter/pkg/token"
+	"unicode"
+)
+
+// baseNames describes the bases a number can be written in, for use in error messages
+var baseNames = map[int]string{
+	2:  "binary",
+	8:  "octal",
+	10: "decimal",
+	16: "hexadecimal",
+}
+
+// readNumber reads the number starting at the current digit.  Integers can be written in decimal, or in hexadecimal,
+// octal or binary after a 0x, 0o or 0b prefix, and any of them can have single underscores between digits to make long
+// numbers easier to read.  A decimal number with a fraction or an exponent is returned as a FLOAT.
+//
+// A malformed number is reported but still returned as an INT or FLOAT token holding everything that was read, so the
+// parser doesn't report a second error for the characters that follow it.
+func (lexer *Lexer) readNumber(start token.Position) token.Token {
+	var tokenType token.TokenType = token.INT
+	var base = 10
+
+	// only the first problem with a number is reported, since the rest tend to follow from it
+	var errorCount = len(lexer.errors)
+
+	if lexer.ch == '0' {
+		switch unicode.ToLower(lexer.peakChar()) {
+		case 'x':
+			base = 16
+		case 'o':
+			base = 8
+		case 'b':
+			base = 2
+		}
+	}
+
+	if base != 10 {
+		lexer.readChar()
+		lexer.readChar()
+
+		if lexer.readDigits(base) == 0 {
+			lexer.addError(diagnostic.INVALID_NUMBER, start, lexer.currentPosition(), "%s literal has no digits",
+				baseNames[base])
+		}
+	} else {
+		lexer.readDigits(base)
+
+		if lexer.ch == '.' && isInteger(lexer.peakChar()) {
+			tokenType = token.FLOAT
+			lexer.readChar()
+			lexer.readDigits(base)
+		}
+
+		if lexer.ch == 'e' || lexer.ch == 'E' {
+			tokenType = token.FLOAT
+			lexer.readChar()
+
+			if lexer.ch == '+' || lexer.ch == '-' {
+				lexer.readChar()
+			}
+
+			if lexer.readDigits(base) == 0 {
+				lexer.addError(diagnostic.INVALID_NUMBER, start, lexer.currentPosition(), "exponent has no digits")
+			}
+		}
+	}
+
+	var literal = lexer.src.slice(start.Offset, lexer.position)
+
+	if len(lexer.errors) > errorCount {
+		lexer.errors = lexer.errors[:errorCount+1]
+	} else if i := invalidSeparator(literal); i >= 0 {
+		var pos = offsetPosition(start, i)
+		lexer.addError(diagnostic.INVALID_NUMBER, pos, offsetPosition(pos, 1), "'_' must separate successive digits")
+	} else if tokenType == token.INT && base == 10 && len(literal) > 1 && literal[0] == '0' {
+		lexer.addError(diagnostic.INVALID_NUMBER, start, lexer.currentPosition(),
+			"decimal literal %s has a leading zero, use the 0o prefix for octal", literal)
+	}
+
+	return token.Token{Type: tokenType, Literal: literal}
+}
+
+// readDigits reads the digits and underscores that follow, returning the number of digits read.  Decimal digits too
+// large for base are reported, but read anyway so that 0b102 is a single malformed number rather than 0b10 and 2.
+func (lexer *Lexer) readDigits(base int) int {
+	var digits = 0
+
+	for lexer.ch == '_' || isInteger(lexer.ch) || base == 16 && isHexDigit(lexer.ch) {
+		if lexer.ch != '_' {
+			if base < 10 && int(lexer.ch-'0') >= base {
+				var pos = lexer.currentPosition()
+				lexer.addError(diagnostic.INVALID_NUMBER, pos, offsetPosition(pos, 1), "invalid digit %q in %s literal",
+					lexer.ch, baseNames[base])
+			}
+
+			digits += 1
+		}
+
+		lexer.readChar()
+	}
+
+	return digits
+}
+
+// invalidSeparator returns the index of the first underscore in literal that isn't between two digits, or -1 when
+// they all are.  An underscore straight after a base prefix, as in 0x_FF, counts as being between digits.
+func invalidSeparator(literal string) int {
+	const (
+		other = iota
+		digit
+		underscore
+	)
+
+	// previous is what the character before i was, a digit, an underscore or anything else such as a . or e
+	var previous = other
+	var hex = false
+	var i = 0
+
+	if len(literal) > 2 && literal[0] == '0' {
+		switch unicode.ToLower(rune(literal[1])) {
+		case 'x':
+			hex = true
+			fallthrough
+		case 'o', 'b':
+			i, previous = 2, digit
+		}
+	}
+
+	for ; i < len(literal); i++ {
+		var ch = rune(literal[i])
+
+		switch {
+		case ch == '_':
+			if previous != digit {
+				return i
+			}
+
+			previous = underscore
+		case isInteger(ch) || hex && isHexDigit(ch):
+			previous = digit
+		default:
+			if previous == underscore {
+				return i - 1
+			}
+
+			previous = other
+		}
+	}
+
+	if previous == underscore {
+		return len(literal) - 1
+	}
+
+	return -1
+}
+
+// offsetPosition returns the position n characters after pos on the same line, for the ASCII characters of a number
+func offsetPosition(pos token.Position, n int) token.Position {
+	pos.Offset += n
+	pos.Column += n
+
+	return pos
+}
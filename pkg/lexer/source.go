@@ -0,0 +1,95 @@
+package lexer
+
+import (
+	"io"
+	"unicode/utf8"
+)
+
+// minRead is the smallest number of bytes asked of the reader at a time
+const minRead = 4096
+
+// source holds the part of the input the lexer still needs.  Input given as a string is held whole, while input read
+// from an io.Reader is read a chunk at a time, and the bytes before the token being read are dropped as the buffer is
+// refilled, so that lexing a large input only needs as much memory as its largest token.
+//
+// Offsets given to source are always counted from the start of the input, however much of it has been dropped.
+type source struct {
+	reader io.Reader
+
+	// buf holds the input from offset base onwards
+	buf  []byte
+	base int
+
+	// keep is the offset of the first byte that may still be asked for, everything before it can be dropped
+	keep int
+
+	// err is the error that stopped the reader, which is io.EOF once all of the input has been read
+	err error
+}
+
+func newStringSource(input string) *source {
+	return &source{buf: []byte(input), err: io.EOF}
+}
+
+func newReaderSource(reader io.Reader) *source {
+	return &source{reader: reader}
+}
+
+// runeAt decodes the character at offset, returning false when offset is at or past the end of the input
+func (src *source) runeAt(offset int) (rune, int, bool) {
+	src.fill(offset + utf8.UTFMax)
+
+	if offset >= src.base+len(src.buf) {
+		return 0, 0, false
+	}
+
+	var ch, width = utf8.DecodeRune(src.buf[offset-src.base:])
+
+	return ch, width, true
+}
+
+// slice returns the input between the offsets start and end, which must not be before the last offset passed to
+// release
+func (src *source) slice(start int, end int) string {
+	return string(src.buf[start-src.base : end-src.base])
+}
+
+// release allows every byte before offset to be dropped
+func (src *source) release(offset int) {
+	if offset > src.keep {
+		src.keep = offset
+	}
+}
+
+// maxEmptyReads is how many reads in a row may return nothing before the reader is assumed to be broken
+const maxEmptyReads = 100
+
+// fill reads from the reader until the buffer holds the input up to end, or the input runs out
+func (src *source) fill(end int) {
+	var emptyReads = 0
+
+	for src.base+len(src.buf) < end && src.err == nil {
+		// make room by dropping what is no longer needed before growing the buffer
+		if dropped := src.keep - src.base; dropped > 0 && cap(src.buf)-len(src.buf) < minRead {
+			src.buf = src.buf[:copy(src.buf, src.buf[dropped:])]
+			src.base = src.keep
+		}
+
+		if cap(src.buf)-len(src.buf) < minRead {
+			var grown = make([]byte, len(src.buf), 2*cap(src.buf)+minRead)
+			copy(grown, src.buf)
+			src.buf = grown
+		}
+
+		n, err := src.reader.Read(src.buf[len(src.buf):cap(src.buf)])
+		src.buf = src.buf[:len(src.buf)+n]
+
+		if err != nil {
+			src.err = err
+		} else if n > 0 {
+			emptyReads = 0
+		} else if emptyReads += 1; emptyReads >= maxEmptyReads {
+			src.err = io.ErrNoProgress
+		}
+	}
+}
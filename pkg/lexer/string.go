@@ -0,0 +1,138 @@
+package lexer
+
+import (
+	"monkeyInterpreter/pkg/diagnostic"
+	"monkeyInterpreter/pkg/token"
+	"strings"
+	"unicode/utf8"
+)
+
+// readString reads a double quoted string starting at the current ", and returns it as a STRING token whose literal
+// has every escape sequence replaced by the character it stands for.  The supported escapes are \n, \t, \r, \", \\ and
+// \u{...}, which holds between 1 and 6 hex digits naming a unicode code point.
+//
+// A string that is still open when the input runs out is returned as an ILLEGAL token holding the source that was read.
+// Unknown escapes are reported but otherwise kept as they were written, so one bad escape doesn't hide the rest of the
+// string from the parser.
+func (lexer *Lexer) readString(start token.Position) token.Token {
+	var out strings.Builder
+
+	// step past the opening quote
+	lexer.readChar()
+
+	for lexer.ch != '"' {
+		if lexer.eof {
+			lexer.addError(diagnostic.UNTERMINATED_STRING, start, lexer.currentPosition(), "string literal not terminated")
+			return token.Token{Type: token.ILLEGAL, Literal: lexer.src.slice(start.Offset, lexer.position)}
+		}
+
+		if lexer.ch == '\\' {
+			lexer.readEscape(&out)
+			continue
+		}
+
+		out.WriteRune(lexer.ch)
+		lexer.readChar()
+	}
+
+	// step past the closing quote
+	lexer.readChar()
+
+	return token.Token{Type: token.STRING, Literal: out.String()}
+}
+
+// readEscape reads the escape sequence starting at the current \ and writes the character it stands for to out
+func (lexer *Lexer) readEscape(out *strings.Builder) {
+	var start = lexer.currentPosition()
+
+	lexer.readChar()
+
+	switch lexer.ch {
+	case 'n':
+		out.WriteByte('\n')
+	case 't':
+		out.WriteByte('\t')
+	case 'r':
+		out.WriteByte('\r')
+	case '"':
+		out.WriteByte('"')
+	case '\\':
+		out.WriteByte('\\')
+	case 'u':
+		lexer.readUnicodeEscape(out, start)
+		return
+	case 0:
+		// let readString report the missing closing quote
+		return
+	default:
+		lexer.readChar()
+		lexer.addError(diagnostic.INVALID_ESCAPE, start, lexer.currentPosition(),
+			"unknown escape sequence %s", lexer.src.slice(start.Offset, lexer.position))
+		out.WriteString(lexer.src.slice(start.Offset, lexer.position))
+		return
+	}
+
+	lexer.readChar()
+}
+
+// readUnicodeEscape reads the {...} of a \u escape, the current character being the u
+func (lexer *Lexer) readUnicodeEscape(out *strings.Builder, start token.Position) {
+	lexer.readChar()
+
+	if lexer.ch != '{' {
+		lexer.addError(diagnostic.INVALID_ESCAPE, start, lexer.currentPosition(), `expected { after \u`)
+		return
+	}
+
+	lexer.readChar()
+
+	var value rune
+	var digits = 0
+
+	for isHexDigit(lexer.ch) {
+		value = value*16 + hexValue(lexer.ch)
+		digits += 1
+		lexer.readChar()
+
+		if digits > 6 {
+			break
+		}
+	}
+
+	if lexer.ch != '}' {
+		lexer.addError(diagnostic.INVALID_ESCAPE, start, lexer.currentPosition(),
+			`expected 1 to 6 hex digits followed by } in \u escape`)
+		return
+	}
+
+	lexer.readChar()
+
+	if digits == 0 || digits > 6 || !utf8.ValidRune(value) {
+		lexer.addError(diagnostic.INVALID_ESCAPE, start, lexer.currentPosition(),
+			"%s is not a valid unicode code point", lexer.src.slice(start.Offset, lexer.position))
+		return
+	}
+
+	out.WriteRune(value)
+}
+
+func (lexer *Lexer) addError(code string, pos token.Position, end token.Position, format string, a ...interface{}) {
+	var diag = diagnostic.New(diagnostic.ERROR, code, token.Token{Pos: pos, End: end}, format, a...)
+
+	lexer.errors = append(lexer.errors, diag)
+}
+
+func isHexDigit(ch rune) bool {
+	return isInteger(ch) || 'a' <= ch && ch <= 'f' || 'A' <= ch && ch <= 'F'
+}
+
+func hexValue(ch rune) rune {
+	switch {
+	case isInteger(ch):
+		return ch - '0'
+	case 'a' <= ch && ch <= 'f':
+		return ch - 'a' + 10
+	default:
+		return ch - 'A' + 10
+	}
+}
@@ -0,0 +1,19 @@
+package lexer
+
+import (
+	"iter"
+	"monkeyInterpreter/pkg/token"
+)
+
+// Tokens returns an iterator over the tokens that are left, ending with the EOF token
+func (lexer *Lexer) Tokens() iter.Seq[token.Token] {
+	return func(yield func(token.Token) bool) {
+		for {
+			var tok = lexer.NextToken()
+
+			if !yield(tok) || tok.Type == token.EOF {
+				return
+			}
+		}
+	}
+}
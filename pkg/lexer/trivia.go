@@ -0,0 +1,39 @@
+package lexer
+
+import "monkeyInterpreter/pkg/token"
+
+// readTokenWithTrivia reads the next token along with the trivia around it.  The trailing trivia stops before the end
+// of the line, leaving the line break to start the leading trivia of the next token, so a comment on a line of its own
+// belongs to the token after it.
+func (lexer *Lexer) readTokenWithTrivia() token.Token {
+	var tok = lexer.readToken()
+
+	tok.Leading = lexer.src.slice(lexer.triviaStart, tok.Pos.Offset)
+	tok.Raw = lexer.src.slice(tok.Pos.Offset, tok.End.Offset)
+
+	if tok.Type != token.EOF {
+		lexer.skipTrailingTrivia()
+		tok.Trailing = lexer.src.slice(tok.End.Offset, lexer.position)
+	}
+
+	lexer.triviaStart = lexer.position
+	lexer.src.release(lexer.triviaStart)
+
+	return tok
+}
+
+// skipTrailingTrivia skips the spaces and comments after a token on the same line.  A block comment that starts on the
+// line is skipped whole, even if it carries on over the lines after.
+func (lexer *Lexer) skipTrailingTrivia() {
+	for {
+		for lexer.ch == ' ' || lexer.ch == '\t' {
+			lexer.readChar()
+		}
+
+		if !lexer.atComment() {
+			return
+		}
+
+		lexer.readComment(lexer.currentPosition())
+	}
+}
@@ -0,0 +1,517 @@
+package lineedit
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// ErrInterrupted is returned by ReadLine when the user presses Ctrl-C, abandoning the line being typed
+var ErrInterrupted = errors.New("interrupted")
+
+// key codes for the control keys the editor understands
+const (
+	ctrlA     = 1
+	ctrlB     = 2
+	ctrlC     = 3
+	ctrlD     = 4
+	ctrlE     = 5
+	ctrlF     = 6
+	ctrlG     = 7
+	backspace = 8
+	tab       = 9
+	lineFeed  = 10
+	ctrlK     = 11
+	ctrlL     = 12
+	enter     = 13
+	ctrlN     = 14
+	ctrlP     = 16
+	ctrlR     = 18
+	ctrlU     = 21
+	ctrlW     = 23
+	escape    = 27
+	delete    = 127
+)
+
+// CompleteFunc returns the words that could complete word, which is the part of an identifier or command ending at the
+// cursor
+type CompleteFunc func(word string) []string
+
+// HighlightFunc returns line decorated with ANSI escape sequences.  It must not change the visible text of the line.
+type HighlightFunc func(line string) string
+
+// Editor reads lines from a terminal, supporting cursor movement, the usual emacs style control keys, a history that
+// can be browsed with the arrow keys or searched with Ctrl-R, and tab completion.
+type Editor struct {
+	in  *bufio.Reader
+	out io.Writer
+
+	// fd is the terminal switched into raw mode while a line is read, or -1 when in is not a terminal
+	fd int
+
+	// Complete provides the candidates for tab completion, which is disabled when it is nil
+	Complete CompleteFunc
+
+	// Highlight decorates the line as it is redrawn, when it is not nil
+	Highlight HighlightFunc
+
+	history *History
+
+	// line and cursor are the state of the line currently being edited, cursor being an index into line
+	line   []rune
+	cursor int
+	prompt string
+
+	// unread holds keys handed back to be read again, such as the one that ended a search
+	unread []rune
+}
+
+// New creates an editor reading key presses from in.  It leaves the terminal mode alone, so in must already deliver
+// every key as it is pressed.
+func New(in io.Reader, out io.Writer) *Editor {
+	return &Editor{in: bufio.NewReader(in), out: out, fd: -1, history: NewHistory("")}
+}
+
+// NewTerminal creates an editor for the terminal behind in, which is put into raw mode while each line is read
+func NewTerminal(in *os.File, out io.Writer) *Editor {
+	var editor = New(in, out)
+	editor.fd = int(in.Fd())
+
+	return editor
+}
+
+// SetHistory replaces the history browsed with the arrow keys, and which accepted lines are added to
+func (e *Editor) SetHistory(history *History) {
+	e.history = history
+}
+
+// ReadLine shows prompt and lets the user edit a line until they press enter, returning the line without its line
+// ending.  io.EOF is returned when the user presses Ctrl-D on an empty line or the input is exhausted, and
+// ErrInterrupted when they press Ctrl-C.
+func (e *Editor) ReadLine(prompt string) (string, error) {
+	if e.fd >= 0 {
+		restore, err := makeRaw(e.fd)
+
+		if err != nil {
+			return "", err
+		}
+
+		defer restore()
+	}
+
+	e.prompt = prompt
+	e.line = e.line[:0]
+	e.cursor = 0
+
+	// browsing moves through the history, with the line being typed kept in place of the entry after the newest
+	var browsing = e.history.Len()
+	var pending string
+
+	e.refresh()
+
+	for {
+		r, err := e.readKey()
+
+		if err != nil {
+			if err == io.EOF && len(e.line) > 0 {
+				return e.accept(), nil
+			}
+
+			return "", err
+		}
+
+		switch r {
+		case enter, lineFeed:
+			return e.accept(), nil
+		case ctrlC:
+			fmt.Fprint(e.out, "^C\n")
+			return "", ErrInterrupted
+		case ctrlD:
+			if len(e.line) == 0 {
+				fmt.Fprint(e.out, "\n")
+				return "", io.EOF
+			}
+
+			e.deleteForward()
+		case ctrlA:
+			e.cursor = 0
+		case ctrlE:
+			e.cursor = len(e.line)
+		case ctrlB:
+			e.moveLeft()
+		case ctrlF:
+			e.moveRight()
+		case ctrlK:
+			e.line = e.line[:e.cursor]
+		case ctrlU:
+			e.line = e.line[e.cursor:]
+			e.cursor = 0
+		case ctrlW:
+			e.deleteWord()
+		case ctrlL:
+			fmt.Fprint(e.out, "\x1b[H\x1b[2J")
+		case ctrlP:
+			browsing, pending = e.browse(browsing, browsing-1, pending)
+		case ctrlN:
+			browsing, pending = e.browse(browsing, browsing+1, pending)
+		case ctrlR:
+			if done := e.search(); done {
+				return e.accept(), nil
+			}
+		case tab:
+			e.complete()
+		case backspace, delete:
+			e.deleteBackward()
+		case escape:
+			switch e.readEscape() {
+			case "[A":
+				browsing, pending = e.browse(browsing, browsing-1, pending)
+			case "[B":
+				browsing, pending = e.browse(browsing, browsing+1, pending)
+			case "[C":
+				e.moveRight()
+			case "[D":
+				e.moveLeft()
+			case "[H", "[1~", "OH":
+				e.cursor = 0
+			case "[F", "[4~", "OF":
+				e.cursor = len(e.line)
+			case "[3~":
+				e.deleteForward()
+			}
+		default:
+			if unicode.IsPrint(r) {
+				e.insert(r)
+			}
+		}
+
+		e.refresh()
+	}
+}
+
+// accept finishes the line being edited, adding it to the history
+func (e *Editor) accept() string {
+	var line = string(e.line)
+
+	e.cursor = len(e.line)
+	e.refresh()
+	fmt.Fprint(e.out, "\n")
+
+	e.history.Add(line)
+
+	return line
+}
+
+func (e *Editor) insert(r rune) {
+	e.line = append(e.line, 0)
+	copy(e.line[e.cursor+1:], e.line[e.cursor:])
+	e.line[e.cursor] = r
+	e.cursor += 1
+}
+
+func (e *Editor) moveLeft() {
+	if e.cursor > 0 {
+		e.cursor -= 1
+	}
+}
+
+func (e *Editor) moveRight() {
+	if e.cursor < len(e.line) {
+		e.cursor += 1
+	}
+}
+
+func (e *Editor) deleteBackward() {
+	if e.cursor > 0 {
+		e.line = append(e.line[:e.cursor-1], e.line[e.cursor:]...)
+		e.cursor -= 1
+	}
+}
+
+func (e *Editor) deleteForward() {
+	if e.cursor < len(e.line) {
+		e.line = append(e.line[:e.cursor], e.line[e.cursor+1:]...)
+	}
+}
+
+// deleteWord deletes the word before the cursor along with any spaces between it and the cursor
+func (e *Editor) deleteWord() {
+	var start = e.cursor
+
+	for start > 0 && unicode.IsSpace(e.line[start-1]) {
+		start -= 1
+	}
+
+	for start > 0 && !unicode.IsSpace(e.line[start-1]) {
+		start -= 1
+	}
+
+	e.line = append(e.line[:start], e.line[e.cursor:]...)
+	e.cursor = start
+}
+
+// browse replaces the line with the history entry at index to, returning the new index and the line that was being
+// typed before browsing started
+func (e *Editor) browse(from int, to int, pending string) (int, string) {
+	if to < 0 || to > e.history.Len() {
+		return from, pending
+	}
+
+	if from == e.history.Len() {
+		pending = string(e.line)
+	}
+
+	if to == e.history.Len() {
+		e.line = []rune(pending)
+	} else {
+		e.line = []rune(e.history.At(to))
+	}
+
+	e.cursor = len(e.line)
+
+	return to, pending
+}
+
+// readKey reads the next key, taking any keys handed back to unread first
+func (e *Editor) readKey() (rune, error) {
+	if len(e.unread) > 0 {
+		var r = e.unread[0]
+		e.unread = e.unread[1:]
+
+		return r, nil
+	}
+
+	r, _, err := e.in.ReadRune()
+
+	return r, err
+}
+
+// readEscape reads the rest of an escape sequence after the escape character, such as the [A sent by the up arrow.
+// When the escape character was pressed on its own, the key after it is left to be read again.
+func (e *Editor) readEscape() string {
+	var seq strings.Builder
+
+	first, err := e.readKey()
+
+	if err != nil {
+		return ""
+	}
+
+	if first != '[' && first != 'O' {
+		e.unread = append([]rune{first}, e.unread...)
+		return ""
+	}
+
+	seq.WriteRune(first)
+
+	for {
+		r, err := e.readKey()
+
+		if err != nil {
+			return ""
+		}
+
+		seq.WriteRune(r)
+
+		// sequences end with a letter or ~, anything before that is a parameter
+		if unicode.IsLetter(r) || r == '~' {
+			return seq.String()
+		}
+	}
+}
+
+// refresh redraws the prompt and line, leaving the terminal cursor where the editing cursor is
+func (e *Editor) refresh() {
+	var line = string(e.line)
+
+	if e.Highlight != nil {
+		line = e.Highlight(line)
+	}
+
+	fmt.Fprintf(e.out, "\r%s%s\x1b[K", e.prompt, line)
+
+	if back := width(e.line[e.cursor:]); back > 0 {
+		fmt.Fprintf(e.out, "\x1b[%dD", back)
+	}
+}
+
+// complete completes the word before the cursor.  A single candidate is inserted, otherwise the longest prefix shared
+// by every candidate is inserted, and when that adds nothing the candidates are listed below the line.
+func (e *Editor) complete() {
+	if e.Complete == nil {
+		return
+	}
+
+	var start = e.cursor
+
+	for start > 0 && isWordRune(e.line[start-1]) {
+		start -= 1
+	}
+
+	var word = string(e.line[start:e.cursor])
+	var candidates = e.Complete(word)
+
+	if len(candidates) == 0 {
+		return
+	}
+
+	var prefix = commonPrefix(candidates)
+
+	if len(candidates) == 1 {
+		prefix += " "
+	}
+
+	if prefix != word && strings.HasPrefix(prefix, word) {
+		for _, r := range []rune(prefix)[len([]rune(word)):] {
+			e.insert(r)
+		}
+
+		return
+	}
+
+	fmt.Fprintf(e.out, "\n%s\n", strings.Join(candidates, "  "))
+}
+
+// search runs a reverse incremental search through the history, started by Ctrl-R.  Typing narrows the search,
+// Ctrl-R again finds an older match, enter accepts the match and submits it, and Ctrl-G, Ctrl-C or escape on its own
+// give up.  Any other key, arrow keys included, leaves the match on the line and is then handled by ReadLine as usual.
+// It reports whether the line should be submitted.
+func (e *Editor) search() bool {
+	var query []rune
+	var original = string(e.line)
+	var match = ""
+	var index = e.history.Len()
+
+	for {
+		fmt.Fprintf(e.out, "\r(reverse-i-search)`%s': %s\x1b[K", string(query), match)
+
+		r, err := e.readKey()
+
+		if err != nil {
+			return false
+		}
+
+		var seq string
+
+		if r == escape {
+			seq = e.readEscape()
+		}
+
+		switch {
+		case r == enter || r == lineFeed:
+			e.line = []rune(match)
+			return true
+		case r == ctrlG || r == ctrlC || (r == escape && seq == ""):
+			e.line = []rune(original)
+			e.cursor = len(e.line)
+			return false
+		case r == ctrlR:
+			// entries repeating the current match are skipped, otherwise the same line could be found over and over
+			for from := index - 1; ; from-- {
+				found, ok := e.history.Search(string(query), from)
+
+				if !ok {
+					break
+				}
+
+				if from = found; e.history.At(found) != match {
+					index, match = found, e.history.At(found)
+					break
+				}
+			}
+		case r == backspace || r == delete:
+			if len(query) > 0 {
+				query = query[:len(query)-1]
+			}
+
+			index, match = e.searchFrom(string(query), e.history.Len()-1, e.history.Len(), "")
+		case unicode.IsPrint(r):
+			query = append(query, r)
+			index, match = e.searchFrom(string(query), index, index, match)
+		default:
+			e.line = []rune(match)
+			e.cursor = len(e.line)
+			e.unread = append(append([]rune{r}, []rune(seq)...), e.unread...)
+			return false
+		}
+
+		e.line = []rune(match)
+		e.cursor = len(e.line)
+	}
+}
+
+// searchFrom looks for query in the history starting at index from, keeping the current index and match when there
+// is nothing older that contains it
+func (e *Editor) searchFrom(query string, from int, index int, match string) (int, string) {
+	if found, ok := e.history.Search(query, from); ok {
+		return found, e.history.At(found)
+	}
+
+	return index, match
+}
+
+// width returns the number of terminal cells taken up by line
+func width(line []rune) int {
+	var cells = 0
+
+	for _, r := range line {
+		switch {
+		case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+			// marks combine with the character before them
+		case unicode.Is(wide, r):
+			cells += 2
+		default:
+			cells += 1
+		}
+	}
+
+	return cells
+}
+
+// wide holds the characters terminals draw two cells wide, the East Asian wide and fullwidth characters and emoji
+var wide = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x1100, Hi: 0x115f, Stride: 1},
+		{Lo: 0x2e80, Hi: 0x303e, Stride: 1},
+		{Lo: 0x3041, Hi: 0x33ff, Stride: 1},
+		{Lo: 0x3400, Hi: 0x4dbf, Stride: 1},
+		{Lo: 0x4e00, Hi: 0x9fff, Stride: 1},
+		{Lo: 0xa000, Hi: 0xa4cf, Stride: 1},
+		{Lo: 0xac00, Hi: 0xd7a3, Stride: 1},
+		{Lo: 0xf900, Hi: 0xfaff, Stride: 1},
+		{Lo: 0xfe30, Hi: 0xfe4f, Stride: 1},
+		{Lo: 0xff00, Hi: 0xff60, Stride: 1},
+		{Lo: 0xffe0, Hi: 0xffe6, Stride: 1},
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x1f300, Hi: 0x1f64f, Stride: 1},
+		{Lo: 0x1f900, Hi: 0x1f9ff, Stride: 1},
+		{Lo: 0x20000, Hi: 0x2fffd, Stride: 1},
+		{Lo: 0x30000, Hi: 0x3fffd, Stride: 1},
+	},
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == ':'
+}
+
+func commonPrefix(words []string) string {
+	var prefix = []rune(words[0])
+
+	for _, word := range words[1:] {
+		var runes = []rune(word)
+		var i = 0
+
+		for i < len(prefix) && i < len(runes) && prefix[i] == runes[i] {
+			i += 1
+		}
+
+		prefix = prefix[:i]
+	}
+
+	return string(prefix)
+}
@@ -0,0 +1,147 @@
+package lineedit
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+)
+
+// MaxHistory is the number of lines a history keeps, older lines being forgotten as new ones are added
+const MaxHistory = 1000
+
+// History is the list of lines previously entered, oldest first.  A history with a path is loaded from and saved to
+// that file, one line per entry, so it survives between sessions.
+type History struct {
+	path    string
+	entries []string
+}
+
+// NewHistory creates an empty history saved to path, or kept only in memory when path is empty
+func NewHistory(path string) *History {
+	return &History{path: path}
+}
+
+// LoadHistory creates a history saved to path, starting with the lines already in the file.  A file that doesn't
+// exist yet is treated as empty.
+func LoadHistory(path string) (*History, error) {
+	var history = NewHistory(path)
+
+	file, err := os.Open(path)
+
+	if os.IsNotExist(err) {
+		return history, nil
+	}
+
+	if err != nil {
+		return history, err
+	}
+
+	defer file.Close()
+
+	var scanner = bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		history.append(scanner.Text())
+	}
+
+	return history, scanner.Err()
+}
+
+// Len returns the number of entries in the history
+func (h *History) Len() int {
+	return len(h.entries)
+}
+
+// At returns the entry at index, 0 being the oldest
+func (h *History) At(index int) string {
+	return h.entries[index]
+}
+
+// Add appends line to the history, and to its file if it has one.  Blank lines, and lines repeating the one before
+// them, aren't added.  Failing to write the file isn't reported, the line is still remembered for this session.
+func (h *History) Add(line string) {
+	if !h.append(line) || h.path == "" {
+		return
+	}
+
+	file, err := os.OpenFile(h.path, os.O_CREATE|os.O_RDWR, 0600)
+
+	if err != nil {
+		return
+	}
+
+	defer file.Close()
+
+	// other sessions may be adding to the same file, so it is locked while it is read and written
+	unlock, err := lockFile(file)
+
+	if err != nil {
+		return
+	}
+
+	defer unlock()
+
+	data, err := io.ReadAll(file)
+
+	if err != nil {
+		return
+	}
+
+	// lines are appended to the file until it holds twice as many as are kept.  It is then rewritten with just the
+	// newest lines in it, whichever session added them, which keeps it bounded while only rewriting it once every
+	// MaxHistory lines.
+	var lines = strings.Split(string(data), "\n")
+
+	if len(lines) < 2*MaxHistory {
+		file.WriteString(line + "\n")
+		return
+	}
+
+	lines = append(lines[len(lines)-MaxHistory:len(lines)-1], line)
+
+	if file.Truncate(0) != nil {
+		return
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return
+	}
+
+	file.WriteString(strings.Join(lines, "\n") + "\n")
+}
+
+// Search returns the index of the newest entry no newer than from that contains query, and whether there was one
+func (h *History) Search(query string, from int) (int, bool) {
+	if from >= len(h.entries) {
+		from = len(h.entries) - 1
+	}
+
+	for i := from; i >= 0; i-- {
+		if strings.Contains(h.entries[i], query) {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+// append adds line to the entries, dropping the oldest once there are more than MaxHistory.  It reports whether the
+// line was added.
+func (h *History) append(line string) bool {
+	if strings.TrimSpace(line) == "" || strings.Contains(line, "\n") {
+		return false
+	}
+
+	if len(h.entries) > 0 && h.entries[len(h.entries)-1] == line {
+		return false
+	}
+
+	h.entries = append(h.entries, line)
+
+	if len(h.entries) > MaxHistory {
+		h.entries = h.entries[len(h.entries)-MaxHistory:]
+	}
+
+	return true
+}
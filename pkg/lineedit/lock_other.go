@@ -0,0 +1,11 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package lineedit
+
+import "os"
+
+// lockFile does nothing on platforms where we don't know how to lock a file, so sessions running side by side may
+// lose each other's lines when the history file is rewritten
+func lockFile(file *os.File) (func(), error) {
+	return func() {}, nil
+}
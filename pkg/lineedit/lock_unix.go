@@ -0,0 +1,18 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package lineedit
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFile takes an exclusive lock on file, waiting for any other process holding it, and returns a function
+// releasing it
+func lockFile(file *os.File) (func(), error) {
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX); err != nil {
+		return nil, err
+	}
+
+	return func() { syscall.Flock(int(file.Fd()), syscall.LOCK_UN) }, nil
+}
@@ -0,0 +1,15 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package lineedit
+
+import "errors"
+
+// IsTerminal always reports false on platforms where we don't know how to put the terminal into raw mode, so callers
+// fall back to reading plain lines
+func IsTerminal(fd int) bool {
+	return false
+}
+
+func makeRaw(fd int) (func(), error) {
+	return nil, errors.New("line editing is not supported on this platform")
+}
@@ -0,0 +1,62 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package lineedit
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// IsTerminal reports whether fd refers to a terminal
+func IsTerminal(fd int) bool {
+	_, err := getTermios(fd)
+	return err == nil
+}
+
+// makeRaw switches the terminal behind fd into a mode where every key press is delivered as soon as it is typed and
+// nothing is echoed, and returns a function restoring the mode it was in before.  Output processing is left on, so
+// the rest of the program can keep writing \n to move to a new line.
+func makeRaw(fd int) (func(), error) {
+	original, err := getTermios(fd)
+
+	if err != nil {
+		return nil, err
+	}
+
+	raw := *original
+	raw.Iflag &^= syscall.BRKINT | syscall.ICRNL | syscall.INPCK | syscall.ISTRIP | syscall.IXON
+	raw.Lflag &^= syscall.ECHO | syscall.ICANON | syscall.IEXTEN | syscall.ISIG
+	raw.Cflag |= syscall.CS8
+	raw.Cc[syscall.VMIN] = 1
+	raw.Cc[syscall.VTIME] = 0
+
+	if err := setTermios(fd, &raw); err != nil {
+		return nil, err
+	}
+
+	return func() { setTermios(fd, original) }, nil
+}
+
+func getTermios(fd int) (*syscall.Termios, error) {
+	var termios syscall.Termios
+
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), uintptr(ioctlGetTermios),
+		uintptr(unsafe.Pointer(&termios)))
+
+	if errno != 0 {
+		return nil, errno
+	}
+
+	return &termios, nil
+}
+
+func setTermios(fd int, termios *syscall.Termios) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), uintptr(ioctlSetTermios),
+		uintptr(unsafe.Pointer(termios)))
+
+	if errno != 0 {
+		return errno
+	}
+
+	return nil
+}
@@ -0,0 +1,18 @@
+package object
+
+// BuiltinFunction is given the environment it was called from, so that it can reach things such as the output of the
+// program
+type BuiltinFunction func(env *Environment, args ...Object) Object
+
+// Builtin is a function provided by the interpreter itself rather than written in Monkey
+type Builtin struct {
+	Fn BuiltinFunction
+}
+
+func (b *Builtin) Type() ObjectType {
+	return BUILTIN_OBJ
+}
+
+func (b *Builtin) Inspect() string {
+	return "builtin function"
+}
@@ -0,0 +1,81 @@
+package object
+
+import (
+	"io"
+	"os"
+	"sort"
+)
+
+// Environment holds the bindings created by let statements and function parameters
+type Environment struct {
+	store map[string]Object
+
+	// outer is the environment this one was created in, and is consulted for any name not bound here
+	outer *Environment
+
+	// output is where the program writes, such as the output of puts, and is inherited from outer when nil
+	output io.Writer
+}
+
+func NewEnvironment() *Environment {
+	return &Environment{store: make(map[string]Object)}
+}
+
+// NewEnclosedEnvironment creates an environment for a function call, whose bindings shadow the ones in outer
+func NewEnclosedEnvironment(outer *Environment) *Environment {
+	var env = NewEnvironment()
+	env.outer = outer
+
+	return env
+}
+
+func (env *Environment) Get(name string) (Object, bool) {
+	obj, ok := env.store[name]
+
+	if !ok && env.outer != nil {
+		obj, ok = env.outer.Get(name)
+	}
+
+	return obj, ok
+}
+
+// Names returns every name that can be looked up in env, including those bound in outer environments, sorted
+// alphabetically
+func (env *Environment) Names() []string {
+	var names []string
+	var seen = make(map[string]bool)
+
+	for e := env; e != nil; e = e.outer {
+		for name := range e.store {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func (env *Environment) Set(name string, val Object) Object {
+	env.store[name] = val
+	return val
+}
+
+// SetOutput changes where the program writes from env, and from every environment created in it
+func (env *Environment) SetOutput(output io.Writer) {
+	env.output = output
+}
+
+// Output returns where the program writes, which is standard output unless SetOutput says otherwise
+func (env *Environment) Output() io.Writer {
+	for e := env; e != nil; e = e.outer {
+		if e.output != nil {
+			return e.output
+		}
+	}
+
+	return os.Stdout
+}
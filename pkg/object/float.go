@@ -0,0 +1,25 @@
+package object
+
+import (
+	"strconv"
+	"strings"
+)
+
+type Float struct {
+	Value float64
+}
+
+func (f *Float) Type() ObjectType {
+	return FLOAT_OBJ
+}
+
+// Inspect always shows a float with a fraction or an exponent, so 3.0 isn't mistaken for the integer 3
+func (f *Float) Inspect() string {
+	var out = strconv.FormatFloat(f.Value, 'g', -1, 64)
+
+	if !strings.ContainsAny(out, ".eIN") {
+		out += ".0"
+	}
+
+	return out
+}
@@ -0,0 +1,91 @@
+package object
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"strings"
+)
+
+// HashKey identifies a value used as a key of a Hash.  Two objects with equal values produce equal keys, even though
+// they are different objects.
+type HashKey struct {
+	Type  ObjectType
+	Value uint64
+}
+
+// Hashable is implemented by the objects that can be used as the key of a Hash
+type Hashable interface {
+	Object
+	HashKey() HashKey
+}
+
+func (b *Boolean) HashKey() HashKey {
+	var value uint64
+
+	if b.Value {
+		value = 1
+	}
+
+	return HashKey{Type: b.Type(), Value: value}
+}
+
+func (i *Integer) HashKey() HashKey {
+	return HashKey{Type: i.Type(), Value: uint64(i.Value)}
+}
+
+func (s *String) HashKey() HashKey {
+	h := fnv.New64a()
+	h.Write([]byte(s.Value))
+
+	return HashKey{Type: s.Type(), Value: h.Sum64()}
+}
+
+// HashPair keeps the original key alongside its value, so the hash can be printed and iterated over
+type HashPair struct {
+	Key   Object
+	Value Object
+}
+
+type Hash struct {
+	Pairs map[HashKey]HashPair
+
+	// order holds the keys in the order they were first inserted, so inspecting a hash is predictable
+	order []HashKey
+}
+
+func NewHash() *Hash {
+	return &Hash{Pairs: make(map[HashKey]HashPair)}
+}
+
+// Set stores value under key, replacing any value already stored under an equal key
+func (h *Hash) Set(key Hashable, value Object) {
+	hashed := key.HashKey()
+
+	if _, ok := h.Pairs[hashed]; !ok {
+		h.order = append(h.order, hashed)
+	}
+
+	h.Pairs[hashed] = HashPair{Key: key, Value: value}
+}
+
+func (h *Hash) Type() ObjectType {
+	return HASH_OBJ
+}
+
+func (h *Hash) Inspect() string {
+	var out bytes.Buffer
+
+	pairs := []string{}
+
+	for _, key := range h.order {
+		pair := h.Pairs[key]
+		pairs = append(pairs, fmt.Sprintf("%s: %s", pair.Key.Inspect(), pair.Value.Inspect()))
+	}
+
+	out.WriteString("{")
+	out.WriteString(strings.Join(pairs, ", "))
+	out.WriteString("}")
+
+	return out.String()
+}
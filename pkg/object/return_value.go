@@ -0,0 +1,15 @@
+package object
+
+// ReturnValue wraps the value of a return statement so the evaluator can unwind to the enclosing program or
+// function body without evaluating any further statements
+type ReturnValue struct {
+	Value Object
+}
+
+func (rv *ReturnValue) Type() ObjectType {
+	return RETURN_VALUE_OBJ
+}
+
+func (rv *ReturnValue) Inspect() string {
+	return rv.Value.Inspect()
+}
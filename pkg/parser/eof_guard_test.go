@@ -0,0 +1,52 @@
+package parser
+
+import (
+	"monkeyInterpreter/pkg/ast"
+	"monkeyInterpreter/pkg/diagnostic"
+	"monkeyInterpreter/pkg/lexer"
+	"monkeyInterpreter/pkg/token"
+	"testing"
+)
+
+// The parse functions all stop at the end of the input, so the guard against one that doesn't can only be reached by
+// swapping in a broken parse function, which needs the internals of the parser.
+func TestGuardStopsLoopPastEOF(t *testing.T) {
+	p := New(lexer.New("let a = 1; let b = 2; { 3"))
+
+	// a hash literal parser that forgot to check for the end of the input while looking for its closing brace
+	p.registerPrefix(token.LBRACE, func() ast.Expression {
+		for !p.currentTokenIs(token.RBRACE) {
+			p.nextToken()
+		}
+
+		return nil
+	})
+
+	program := p.ParseProgram()
+
+	if program.String() != "let a = 1;let b = 2;" {
+		t.Errorf("expected the statements before the loop to be kept, got=%q", program.String())
+	}
+
+	errs := p.Errors()
+
+	if len(errs) != 1 || errs[0].Code != diagnostic.UNEXPECTED_EOF || errs[0].Pos.String() != "1:26" {
+		t.Errorf("expected an unexpected end of input error at 1:26, got=%v", errs)
+	}
+}
+
+func TestGuardPassesOnOtherPanics(t *testing.T) {
+	p := New(lexer.New("{"))
+
+	p.registerPrefix(token.LBRACE, func() ast.Expression {
+		panic("something else")
+	})
+
+	defer func() {
+		if r := recover(); r != "something else" {
+			t.Errorf("expected the panic to be passed on, got=%v", r)
+		}
+	}()
+
+	p.ParseProgram()
+}
@@ -1,9 +1,9 @@
 package parser
 
 import (
-	"fmt"
+	"errors"
 	"monkeyInterpreter/pkg/ast"
-	"monkeyInterpreter/pkg/lexer"
+	"monkeyInterpreter/pkg/diagnostic"
 	"monkeyInterpreter/pkg/token"
 	"strconv"
 )
@@ -11,32 +11,53 @@ import (
 const (
 	_ int = iota
 	LOWEST
+	LOGICAL_OR
+	LOGICAL_AND
 	EQUALS
 	LESSGREATER
 	SUM
 	PRODUCT
 	PREFIX
 	CALL
+	INDEX
 )
 
 var precedences = map[token.TokenType]int{
+	token.OR:          LOGICAL_OR,
+	token.AND:         LOGICAL_AND,
 	token.EQ:          EQUALS,
 	token.NOT_EQ:      EQUALS,
 	token.LESSTHAN:    LESSGREATER,
 	token.GREATERTHAN: LESSGREATER,
+	token.LT_EQ:       LESSGREATER,
+	token.GT_EQ:       LESSGREATER,
 	token.PLUS:        SUM,
 	token.MINUS:       SUM,
 	token.SLASH:       PRODUCT,
 	token.ASTERISK:    PRODUCT,
+	token.PERCENT:     PRODUCT,
+	token.LPAREN:      CALL,
+	token.LBRACKET:    INDEX,
 }
 
+// maxEOFAdvances is how many times the parser may be asked to step past the EOF token before it decides a parse
+// function is stuck in a loop that will never terminate
+const maxEOFAdvances = 64
+
+// errPastEOF is raised with panic by nextToken when a parse function keeps advancing past the end of the input
+var errPastEOF = errors.New("parser advanced past the end of the input")
+
+// DefaultMaxErrors is the number of errors a Parser reports before it gives up on the rest of the input
+const DefaultMaxErrors = 10
+
 type (
 	prefixParseFn func() ast.Expression
 	infixParseFn  func(ast.Expression) ast.Expression
 )
 
 type Parser struct {
-	lex *lexer.Lexer
+	// tokens supplies the tokens being parsed, with any comments already removed
+	tokens TokenSource
 
 	// currentToken represents the token we are currently parsing
 	currentToken token.Token
@@ -44,23 +65,56 @@ type Parser struct {
 	// peekToken is the next token returned from lex.  This allows us to look ahead when forming the AST
 	peekToken token.Token
 
-	errors []string
+	// previousToken is the token that was current before currentToken, used to tell when a token starts a new line
+	previousToken token.Token
+
+	// eofAdvances counts the calls to nextToken made while the current token was already EOF
+	eofAdvances int
+
+	// tokenCount is the number of tokens that have been made current so far, so we can tell whether the parser has
+	// moved since a given point
+	tokenCount int
+
+	errors    []diagnostic.Diagnostic
+	maxErrors int
+
+	// lexErrors is how many of the errors reported by lex have already been copied into errors
+	lexErrors int
+
+	// blockDepth is the number of block statements currently being parsed
+	blockDepth int
+
+	// panicking is set as soon as an error is raised, and stays set until synchronize has found the start of the next
+	// statement.  Parse functions use it to give up early instead of reporting errors caused by the first one.
+	panicking bool
 
 	prefixParseFns map[token.TokenType]prefixParseFn
 	infixParseFns  map[token.TokenType]infixParseFn
 }
 
-func New(lex *lexer.Lexer) *Parser {
+// New creates a parser for the tokens from src, which is usually a *lexer.Lexer.  Comments are skipped, and when src
+// also implements ErrorSource its errors are reported along with the parser's own.
+func New(src TokenSource) *Parser {
 	var p = &Parser{
-		lex:    lex,
-		errors: []string{},
+		tokens:    SkipComments(src),
+		errors:    []diagnostic.Diagnostic{},
+		maxErrors: DefaultMaxErrors,
 	}
 
 	p.prefixParseFns = make(map[token.TokenType]prefixParseFn)
 	p.registerPrefix(token.IDENT, p.parseIdentifier)
 	p.registerPrefix(token.INT, p.parseIntegerLiteral)
+	p.registerPrefix(token.FLOAT, p.parseFloatLiteral)
+	p.registerPrefix(token.STRING, p.parseStringLiteral)
 	p.registerPrefix(token.BANG, p.parsePrefixExpression)
 	p.registerPrefix(token.MINUS, p.parsePrefixExpression)
+	p.registerPrefix(token.TRUE, p.parseBoolean)
+	p.registerPrefix(token.FALSE, p.parseBoolean)
+	p.registerPrefix(token.LPAREN, p.parseGroupedExpression)
+	p.registerPrefix(token.IF, p.parseIfExpression)
+	p.registerPrefix(token.FUNCTION, p.parseFunctionLiteral)
+	p.registerPrefix(token.LBRACKET, p.parseArrayLiteral)
+	p.registerPrefix(token.LBRACE, p.parseHashLiteral)
 
 	p.infixParseFns = make(map[token.TokenType]infixParseFn)
 	p.registerInfix(token.PLUS, p.parseInfixExpression)
@@ -71,6 +125,13 @@ func New(lex *lexer.Lexer) *Parser {
 	p.registerInfix(token.NOT_EQ, p.parseInfixExpression)
 	p.registerInfix(token.LESSTHAN, p.parseInfixExpression)
 	p.registerInfix(token.GREATERTHAN, p.parseInfixExpression)
+	p.registerInfix(token.LT_EQ, p.parseInfixExpression)
+	p.registerInfix(token.GT_EQ, p.parseInfixExpression)
+	p.registerInfix(token.PERCENT, p.parseInfixExpression)
+	p.registerInfix(token.AND, p.parseInfixExpression)
+	p.registerInfix(token.OR, p.parseInfixExpression)
+	p.registerInfix(token.LPAREN, p.parseCallExpression)
+	p.registerInfix(token.LBRACKET, p.parseIndexExpression)
 
 	// read two tokens to set current and peek
 	p.nextToken()
@@ -79,33 +140,146 @@ func New(lex *lexer.Lexer) *Parser {
 	return p
 }
 
+// ParseProgram parses every statement from the tokens, and then stops the token source if it is a Stopper
 func (p *Parser) ParseProgram() *ast.Program {
+	defer stopSource(p.tokens)
+
 	program := &ast.Program{}
 
 	program.Statements = []ast.Statement{}
 
-	for !p.currentTokenIs(token.EOF) {
+	p.parseStatements(program)
+
+	return program
+}
+
+// parseStatements appends statements to program until the input is exhausted or too many errors have been found
+func (p *Parser) parseStatements(program *ast.Program) {
+	defer p.recoverPastEOF()
+
+	for !p.currentTokenIs(token.EOF) && !p.tooManyErrors() {
+		var start = p.tokenCount
+
 		statemnt := p.parseStatement()
 
+		// a statement that produced errors is dropped, and we skip ahead to where the next one is likely to begin so a
+		// single mistake doesn't turn into a cascade of follow-on errors
+		if p.panicking {
+			p.synchronize(start)
+			continue
+		}
+
 		if statemnt != nil {
 			program.Statements = append(program.Statements, statemnt)
 		}
 
 		p.nextToken()
 	}
+}
 
-	return program
+// SetMaxErrors changes the number of errors reported before parsing stops.  A max of 0 or less means there is no limit.
+func (p *Parser) SetMaxErrors(max int) {
+	p.maxErrors = max
 }
 
-func (p *Parser) Errors() []string {
+// Errors returns the problems found while parsing, in the order they were found
+func (p *Parser) Errors() []diagnostic.Diagnostic {
 	return p.errors
 }
 
 // nextToken advances the parser's tokens by setting the currentToken to the peekToken, and then setting peekToken to
 // the next token retrieved by lex
 func (p *Parser) nextToken() {
+	// there is nothing after EOF, so there is no point asking lex for more.  A parse function that keeps trying is
+	// looping without checking for the end of the input, and is stopped before it hangs the caller.
+	if p.currentTokenIs(token.EOF) {
+		p.eofAdvances += 1
+
+		if p.eofAdvances > maxEOFAdvances {
+			panic(errPastEOF)
+		}
+
+		return
+	}
+
+	p.previousToken = p.currentToken
 	p.currentToken = p.peekToken
-	p.peekToken = p.lex.NextToken()
+	p.peekToken = p.tokens.NextToken()
+	p.tokenCount += 1
+
+	// problems found by the lexer are reported alongside our own, but they don't put the parser into panic mode since
+	// the token that caused them may still parse perfectly well
+	if errs := sourceErrors(p.tokens); len(errs) > p.lexErrors {
+		for _, diag := range errs[p.lexErrors:] {
+			p.recordError(diag)
+		}
+
+		p.lexErrors = len(errs)
+	}
+}
+
+// recoverPastEOF turns the panic raised by nextToken for a parse function stuck at the end of the input into an
+// error, leaving every statement parsed before it in place.  Any other panic is passed on.
+func (p *Parser) recoverPastEOF() {
+	if r := recover(); r != nil {
+		if r != errPastEOF {
+			panic(r)
+		}
+
+		p.errorAt(p.currentToken, diagnostic.UNEXPECTED_EOF, "unexpected end of input")
+	}
+}
+
+// synchronize discards tokens after a syntax error until the current token is the first token of the next statement.
+// Statements are assumed to begin after a semicolon or closing brace, at a let or return keyword, or at the start of a
+// new line.  Inside a block it stops at the closing brace of the block instead.  start is the tokenCount at the
+// beginning of the failed statement, and the token at that point is always skipped so we are guaranteed to make
+// progress.
+func (p *Parser) synchronize(start int) {
+	p.panicking = false
+
+	// braces opened while skipping are skipped along with everything up to their matching close, so the body of a
+	// broken function literal isn't mistaken for the statements around it
+	var depth = 0
+
+	for !p.currentTokenIs(token.EOF) {
+		if p.tokenCount > start && depth == 0 {
+			if p.currentTokenIs(token.LET) || p.currentTokenIs(token.RETURN) || p.startsLine() {
+				return
+			}
+		}
+
+		switch {
+		case p.currentTokenIs(token.LBRACE):
+			depth += 1
+		case p.currentTokenIs(token.RBRACE) && depth > 0:
+			depth -= 1
+		case p.currentTokenIs(token.RBRACE) && p.blockDepth > 0 && p.tokenCount > start:
+			// the closing brace of the enclosing block is left for parseBlockStatement to consume
+			return
+		case p.currentTokenIs(token.SEMICOLON) && depth == 0:
+			p.nextToken()
+			return
+		case p.currentTokenIs(token.RBRACE):
+			// a stray brace closing something opened before the error, which is often followed by the semicolon that
+			// ended the statement it belonged to
+			p.nextToken()
+
+			if p.currentTokenIs(token.SEMICOLON) {
+				p.nextToken()
+			}
+
+			return
+		}
+
+		p.nextToken()
+	}
+}
+
+// startsLine reports whether the current token is the first one on its line
+func (p *Parser) startsLine() bool {
+	return p.currentToken.Pos.IsValid() && p.previousToken.End.IsValid() &&
+		p.currentToken.Pos.Line > p.previousToken.End.Line
 }
 
 func (p *Parser) parseStatement() ast.Statement {
@@ -136,9 +310,7 @@ func (p *Parser) parseLetStatement() *ast.LetStatement {
 
 	state.Value = p.parseExpression(LOWEST)
 
-	if p.peekTokenIs(token.SEMICOLON) {
-		p.nextToken()
-	}
+	p.skipOptionalSemicolon()
 
 	return state
 }
@@ -150,9 +322,7 @@ func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 
 	stmt.ReturnValue = p.parseExpression(LOWEST)
 
-	for !p.currentTokenIs(token.SEMICOLON) {
-		p.nextToken()
-	}
+	p.skipOptionalSemicolon()
 
 	return stmt
 }
@@ -162,13 +332,19 @@ func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
 
 	stmt.Expression = p.parseExpression(LOWEST)
 
-	if p.peekTokenIs(token.SEMICOLON) {
-		p.nextToken()
-	}
+	p.skipOptionalSemicolon()
 
 	return stmt
 }
 
+// skipOptionalSemicolon steps onto the semicolon ending a statement if there is one.  A statement that failed is left
+// where the error happened, so synchronize starts looking for the next statement from there.
+func (p *Parser) skipOptionalSemicolon() {
+	if !p.panicking && p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+}
+
 func (p *Parser) parseExpression(precedence int) ast.Expression {
 	prefix := p.prefixParseFns[p.currentToken.Type]
 
@@ -179,7 +355,7 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 
 	leftExp := prefix()
 
-	for !p.peekTokenIs(token.SEMICOLON) && precedence < p.peekPrecedence() {
+	for !p.panicking && !p.peekTokenIs(token.SEMICOLON) && precedence < p.peekPrecedence() {
 		infix := p.infixParseFns[p.peekToken.Type]
 
 		if infix == nil {
@@ -198,17 +374,314 @@ func (p *Parser) parseIntegerLiteral() ast.Expression {
 
 	value, err := strconv.ParseInt(p.currentToken.Literal, 0, 64)
 
-	if err != nil {
-		msg := fmt.Sprintf("count not parse %q as integer", p.currentToken.Literal)
-		p.errors = append(p.errors, msg)
+	if errors.Is(err, strconv.ErrRange) {
+		p.errorAt(p.currentToken, diagnostic.INVALID_INTEGER, "integer literal %s does not fit in 64 bits",
+			p.currentToken.Literal)
+
+		return nil
+	}
+
+	// a malformed literal, such as one with a misplaced underscore, is normally reported by the lexer, in which case
+	// it is kept with a value of 0 rather than reporting the same problem twice
+	if err != nil && !p.sourceReported(p.currentToken) {
+		p.errorAt(p.currentToken, diagnostic.INVALID_INTEGER, "malformed integer literal %s", p.currentToken.Literal)
+
+		return nil
+	}
+
+	lit.Value = value
+
+	return lit
+}
+
+func (p *Parser) parseFloatLiteral() ast.Expression {
+	lit := &ast.FloatLiteral{Token: p.currentToken}
+
+	value, err := strconv.ParseFloat(p.currentToken.Literal, 64)
+
+	if errors.Is(err, strconv.ErrRange) {
+		p.errorAt(p.currentToken, diagnostic.INVALID_FLOAT, "float literal %s is out of range", p.currentToken.Literal)
+
+		return nil
+	}
+
+	// as with integers, a malformed literal is normally reported by the lexer
+	if err != nil && !p.sourceReported(p.currentToken) {
+		p.errorAt(p.currentToken, diagnostic.INVALID_FLOAT, "malformed float literal %s", p.currentToken.Literal)
 
 		return nil
 	}
 
 	lit.Value = value
+
+	return lit
+}
+
+// sourceReported reports whether the token source, such as the lexer, has already reported a problem within tok
+func (p *Parser) sourceReported(tok token.Token) bool {
+	if !tok.Pos.IsValid() {
+		return false
+	}
+
+	for _, diag := range sourceErrors(p.tokens) {
+		if diag.Pos.Filename == tok.Pos.Filename && diag.Pos.Offset >= tok.Pos.Offset && diag.Pos.Offset < tok.End.Offset {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *Parser) parseStringLiteral() ast.Expression {
+	return &ast.StringLiteral{Token: p.currentToken, Value: p.currentToken.Literal}
+}
+
+func (p *Parser) parseBoolean() ast.Expression {
+	return &ast.Boolean{Token: p.currentToken, Value: p.currentTokenIs(token.TRUE)}
+}
+
+func (p *Parser) parseGroupedExpression() ast.Expression {
+	p.nextToken()
+
+	exp := p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	return exp
+}
+
+func (p *Parser) parseIfExpression() ast.Expression {
+	expression := &ast.IfExpression{Token: p.currentToken}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+
+	p.nextToken()
+	expression.Condition = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	expression.Consequence = p.parseBlockStatement()
+
+	if !p.panicking && p.peekTokenIs(token.ELSE) {
+		p.nextToken()
+
+		if !p.expectPeek(token.LBRACE) {
+			return nil
+		}
+
+		expression.Alternative = p.parseBlockStatement()
+	}
+
+	return expression
+}
+
+// parseBlockStatement parses the statements between the current { and its matching }, leaving the } as the current
+// token.  Errors inside the block are recovered from here, so one bad statement doesn't throw away the whole block.
+func (p *Parser) parseBlockStatement() *ast.BlockStatement {
+	block := &ast.BlockStatement{Token: p.currentToken}
+	block.Statements = []ast.Statement{}
+
+	p.blockDepth += 1
+	defer func() { p.blockDepth -= 1 }()
+
+	p.nextToken()
+
+	for !p.currentTokenIs(token.RBRACE) && !p.currentTokenIs(token.EOF) {
+		var start = p.tokenCount
+
+		stmt := p.parseStatement()
+
+		if p.panicking {
+			p.synchronize(start)
+			continue
+		}
+
+		if stmt != nil {
+			block.Statements = append(block.Statements, stmt)
+		}
+
+		p.nextToken()
+	}
+
+	if !p.currentTokenIs(token.RBRACE) {
+		p.unexpectedTokenError(p.currentToken, token.RBRACE)
+		return block
+	}
+
+	block.Rbrace = p.currentToken
+
+	return block
+}
+
+func (p *Parser) parseFunctionLiteral() ast.Expression {
+	lit := &ast.FunctionLiteral{Token: p.currentToken}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+
+	lit.Parameters = p.parseFunctionParameters()
+
+	if p.panicking {
+		return nil
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	lit.Body = p.parseBlockStatement()
+
 	return lit
 }
 
+// parseFunctionParameters parses the comma separated identifiers following the current (, leaving the closing ) as
+// the current token
+func (p *Parser) parseFunctionParameters() []*ast.Identifier {
+	identifiers := []*ast.Identifier{}
+
+	if p.peekTokenIs(token.RPAREN) {
+		p.nextToken()
+		return identifiers
+	}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+
+	identifiers = append(identifiers, &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal})
+
+	for p.peekTokenIs(token.COMMA) {
+		p.nextToken()
+
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+
+		identifiers = append(identifiers, &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal})
+	}
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	return identifiers
+}
+
+func (p *Parser) parseCallExpression(function ast.Expression) ast.Expression {
+	exp := &ast.CallExpression{Token: p.currentToken, Function: function}
+
+	exp.Arguments = p.parseExpressionList(token.RPAREN)
+
+	if p.panicking {
+		return nil
+	}
+
+	exp.Rparen = p.currentToken
+
+	return exp
+}
+
+// parseExpressionList parses the comma separated expressions following the current token up to end, leaving end as
+// the current token
+func (p *Parser) parseExpressionList(end token.TokenType) []ast.Expression {
+	list := []ast.Expression{}
+
+	if p.peekTokenIs(end) {
+		p.nextToken()
+		return list
+	}
+
+	p.nextToken()
+	list = append(list, p.parseExpression(LOWEST))
+
+	for !p.panicking && p.peekTokenIs(token.COMMA) {
+		p.nextToken()
+		p.nextToken()
+		list = append(list, p.parseExpression(LOWEST))
+	}
+
+	if !p.expectPeek(end) {
+		return nil
+	}
+
+	return list
+}
+
+func (p *Parser) parseArrayLiteral() ast.Expression {
+	array := &ast.ArrayLiteral{Token: p.currentToken}
+
+	array.Elements = p.parseExpressionList(token.RBRACKET)
+
+	if p.panicking {
+		return nil
+	}
+
+	array.Rbracket = p.currentToken
+
+	return array
+}
+
+func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
+	exp := &ast.IndexExpression{Token: p.currentToken, Left: left}
+
+	p.nextToken()
+	exp.Index = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
+
+	exp.Rbracket = p.currentToken
+
+	return exp
+}
+
+func (p *Parser) parseHashLiteral() ast.Expression {
+	hash := &ast.HashLiteral{Token: p.currentToken}
+	hash.Pairs = []ast.HashPair{}
+
+	for !p.peekTokenIs(token.RBRACE) {
+		p.nextToken()
+		key := p.parseExpression(LOWEST)
+
+		if !p.expectPeek(token.COLON) {
+			return nil
+		}
+
+		p.nextToken()
+		value := p.parseExpression(LOWEST)
+
+		if p.panicking {
+			return nil
+		}
+
+		hash.Pairs = append(hash.Pairs, ast.HashPair{Key: key, Value: value})
+
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+
+	hash.Rbrace = p.currentToken
+
+	return hash
+}
+
 func (p *Parser) parsePrefixExpression() ast.Expression {
 	expression := &ast.PrefixExpression{
 		Token:    p.currentToken,
@@ -255,8 +728,64 @@ func (p *Parser) expectPeek(t token.TokenType) bool {
 }
 
 func (p *Parser) peekError(t token.TokenType) {
-	var msg = fmt.Sprintf("expected next token to be %s, got %s instead", t, p.peekToken.Type)
-	p.errors = append(p.errors, msg)
+	p.unexpectedTokenError(p.peekToken, t)
+}
+
+func (p *Parser) unexpectedTokenError(tok token.Token, expected token.TokenType) {
+	var diag = diagnostic.New(diagnostic.ERROR, diagnostic.UNEXPECTED_TOKEN, tok,
+		"expected next token to be %s, got %s instead", expected, tok.Type)
+
+	diag.Expected = expected
+	diag.Got = tok.Type
+
+	p.addError(diag)
+}
+
+func (p *Parser) errorAt(tok token.Token, code string, format string, a ...interface{}) {
+	p.addError(diagnostic.New(diagnostic.ERROR, code, tok, format, a...))
+}
+
+// addError records diag and puts the parser into panic mode, unless it is already recovering from an earlier error in
+// which case diag is most likely caused by that error and is dropped
+func (p *Parser) addError(diag diagnostic.Diagnostic) {
+	if p.panicking {
+		return
+	}
+
+	p.panicking = true
+	p.recordError(diag)
+}
+
+// recordError adds diag to the errors of the parser, unless another error was already reported at the same position or
+// we have already reported as many errors as we are allowed to.  Errors without a position, such as those about tokens
+// built by hand, can't be told apart that way and are all kept.  When the limit is reached a final note is added to say
+// parsing stopped early.
+func (p *Parser) recordError(diag diagnostic.Diagnostic) {
+	if p.tooManyErrors() {
+		return
+	}
+
+	for _, existing := range p.errors {
+		if diag.Pos.IsValid() && existing.Pos == diag.Pos {
+			return
+		}
+	}
+
+	p.errors = append(p.errors, diag)
+
+	if p.tooManyErrors() {
+		p.errors = append(p.errors, diagnostic.Diagnostic{
+			Severity: diagnostic.NOTE,
+			Code:     diagnostic.TOO_MANY_ERRORS,
+			Message:  "too many errors, stopping",
+			Pos:      diag.Pos,
+			End:      diag.End,
+		})
+	}
+}
+
+func (p *Parser) tooManyErrors() bool {
+	return p.maxErrors > 0 && len(p.errors) >= p.maxErrors
 }
 
 func (p *Parser) peekPrecedence() int {
@@ -288,6 +817,5 @@ func (p *Parser) parseIdentifier() ast.Expression {
 }
 
 func (p *Parser) noPrefixParseFnError(t token.TokenType) {
-	msg := fmt.Sprintf("no prefix parse function for %s found", t)
-	p.errors = append(p.errors, msg)
+	p.errorAt(p.currentToken, diagnostic.EXPECTED_EXPRESSION, "expected an expression, got %s instead", t)
 }
@@ -0,0 +1,166 @@
+package parser
+
+import (
+	"iter"
+	"monkeyInterpreter/pkg/diagnostic"
+	"monkeyInterpreter/pkg/token"
+)
+
+// TokenSource supplies the tokens for a Parser.  Once the tokens run out NextToken must return an EOF token, and keep
+// returning one however many more times it is called.
+type TokenSource interface {
+	NextToken() token.Token
+}
+
+// ErrorSource is implemented by token sources that find problems of their own, such as *lexer.Lexer.  Errors returns
+// every problem found so far, and may only ever grow.
+type ErrorSource interface {
+	Errors() []diagnostic.Diagnostic
+}
+
+// sourceErrors returns the errors found by src, if it reports any
+func sourceErrors(src TokenSource) []diagnostic.Diagnostic {
+	if errorSource, ok := src.(ErrorSource); ok {
+		return errorSource.Errors()
+	}
+
+	return nil
+}
+
+// sliceSource hands out a fixed list of tokens
+type sliceSource struct {
+	tokens []token.Token
+	next   int
+}
+
+// FromSlice returns a source for tokens, which need not end with an EOF token, so that a parser can be given tokens
+// built by hand or saved from an earlier pass.  Tokens without positions are fine, though errors about them won't
+// say where they are.
+func FromSlice(tokens []token.Token) TokenSource {
+	return &sliceSource{tokens: tokens}
+}
+
+func (src *sliceSource) NextToken() token.Token {
+	if src.next < len(src.tokens) {
+		src.next += 1
+		return src.tokens[src.next-1]
+	}
+
+	return eofAfter(src.tokens)
+}
+
+// seqSource pulls tokens from an iterator one at a time
+type seqSource struct {
+	next func() (token.Token, bool)
+	stop func()
+
+	// last is the last token pulled from the iterator, and the one repeated once the iterator is finished if it was
+	// an EOF token
+	last token.Token
+	done bool
+}
+
+// Stopper is implemented by token sources that hold on to something until their tokens have been read to the end, such
+// as the ones from FromSeq.  ParseProgram calls Stop once it is done with the tokens, whether or not it read all of
+// them, and the source returns EOF tokens from then on.
+type Stopper interface {
+	Stop()
+}
+
+// stopSource stops src, if it needs stopping
+func stopSource(src TokenSource) {
+	if stopper, ok := src.(Stopper); ok {
+		stopper.Stop()
+	}
+}
+
+// FromSeq returns a source for the tokens produced by seq, such as the ones from lexer.Tokens, or a pass that rewrites
+// them on their way to the parser.  The iterator is stopped once it produces an EOF token or runs out, and if it runs
+// out without an EOF token one is added.  The iterator runs in a goroutine of its own until it is stopped, so a source
+// that is not read to the end, or handed to a parser that gives up early, must be stopped with Stop.
+func FromSeq(seq iter.Seq[token.Token]) TokenSource {
+	var next, stop = iter.Pull(seq)
+
+	return &seqSource{next: next, stop: stop}
+}
+
+func (src *seqSource) NextToken() token.Token {
+	if src.done {
+		return src.last
+	}
+
+	tok, ok := src.next()
+
+	if !ok {
+		tok = eofAfter([]token.Token{src.last})
+	}
+
+	if tok.Type == token.EOF {
+		src.done = true
+		src.stop()
+	}
+
+	src.last = tok
+
+	return tok
+}
+
+func (src *seqSource) Stop() {
+	if src.done {
+		return
+	}
+
+	src.done = true
+	src.stop()
+	src.last = eofAfter([]token.Token{src.last})
+}
+
+// eofAfter returns an EOF token positioned at the end of the last of tokens
+func eofAfter(tokens []token.Token) token.Token {
+	var eof = token.Token{Type: token.EOF}
+
+	if len(tokens) > 0 {
+		var last = tokens[len(tokens)-1]
+
+		if last.Type == token.EOF {
+			return last
+		}
+
+		eof.Pos, eof.End = last.End, last.End
+	}
+
+	return eof
+}
+
+// commentFilter drops the comments from the tokens of another source
+type commentFilter struct {
+	src TokenSource
+}
+
+// SkipComments returns a source for the tokens from src other than comments.  The errors from src, if it reports
+// any, are passed on.
+func SkipComments(src TokenSource) TokenSource {
+	if _, ok := src.(*commentFilter); ok {
+		return src
+	}
+
+	return &commentFilter{src: src}
+}
+
+func (filter *commentFilter) NextToken() token.Token {
+	var tok = filter.src.NextToken()
+
+	for tok.Type == token.COMMENT || tok.Type == token.DOC_COMMENT {
+		tok = filter.src.NextToken()
+	}
+
+	return tok
+}
+
+func (filter *commentFilter) Errors() []diagnostic.Diagnostic {
+	return sourceErrors(filter.src)
+}
+
+func (filter *commentFilter) Stop() {
+	stopSource(filter.src)
+}
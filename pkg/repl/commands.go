@@ -0,0 +1,113 @@
+package repl
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// metaCommand is a colon prefixed command that controls the repl itself rather than being evaluated
+type metaCommand struct {
+	name  string
+	usage string
+	help  string
+	run   func(r *Repl, args []string)
+}
+
+// metaCommands is filled in by init, since :help needs to list the commands it is one of
+var metaCommands []metaCommand
+
+func init() {
+	metaCommands = []metaCommand{
+		{"help", ":help", "list the available commands", (*Repl).helpCommand},
+		{"load", ":load <file>", "run a file in the current session", (*Repl).loadCommand},
+		{"env", ":env", "list the bindings currently in scope", (*Repl).envCommand},
+		{"reset", ":reset", "clear every binding in the session", (*Repl).resetCommand},
+		{"mode", ":mode tokens|ast|eval", "choose what is printed for each input", (*Repl).modeCommand},
+		{"time", ":time", "toggle reporting how long parsing and evaluation take", (*Repl).timeCommand},
+		{"quit", ":quit", "end the session", (*Repl).quitCommand},
+	}
+}
+
+func isMetaCommand(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), ":")
+}
+
+func (r *Repl) runMetaCommand(line string) {
+	var fields = strings.Fields(strings.TrimPrefix(strings.TrimSpace(line), ":"))
+
+	if len(fields) == 0 {
+		fmt.Fprintln(r.out, "missing command, type :help to list the commands")
+		return
+	}
+
+	for _, cmd := range metaCommands {
+		if cmd.name == fields[0] {
+			cmd.run(r, fields[1:])
+			return
+		}
+	}
+
+	fmt.Fprintf(r.out, "unknown command :%s, type :help to list the commands\n", fields[0])
+}
+
+func (r *Repl) helpCommand(_ []string) {
+	for _, cmd := range metaCommands {
+		fmt.Fprintf(r.out, "%-24s %s\n", cmd.usage, cmd.help)
+	}
+}
+
+func (r *Repl) loadCommand(args []string) {
+	if len(args) != 1 {
+		fmt.Fprintln(r.out, "usage: :load <file>")
+		return
+	}
+
+	source, err := os.ReadFile(args[0])
+
+	if err != nil {
+		fmt.Fprintf(r.out, "could not load file: %s\n", err)
+		return
+	}
+
+	r.eval(args[0], string(source))
+}
+
+func (r *Repl) envCommand(_ []string) {
+	for _, name := range r.env.Names() {
+		value, _ := r.env.Get(name)
+		fmt.Fprintf(r.out, "%s = %s\n", name, value.Inspect())
+	}
+}
+
+func (r *Repl) resetCommand(_ []string) {
+	r.env = r.newEnvironment()
+}
+
+func (r *Repl) modeCommand(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintf(r.out, "mode is %s\n", r.mode)
+		return
+	}
+
+	switch mode := Mode(args[0]); mode {
+	case MODE_EVAL, MODE_TOKENS, MODE_AST:
+		r.mode = mode
+	default:
+		fmt.Fprintln(r.out, "usage: :mode tokens|ast|eval")
+	}
+}
+
+func (r *Repl) timeCommand(_ []string) {
+	r.timing = !r.timing
+
+	if r.timing {
+		fmt.Fprintln(r.out, "timing on")
+	} else {
+		fmt.Fprintln(r.out, "timing off")
+	}
+}
+
+func (r *Repl) quitCommand(_ []string) {
+	r.quit = true
+}
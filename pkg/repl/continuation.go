@@ -0,0 +1,72 @@
+package repl
+
+import (
+	"monkeyInterpreter/pkg/diagnostic"
+	"monkeyInterpreter/pkg/lexer"
+	"monkeyInterpreter/pkg/token"
+)
+
+// closers maps every opening bracket to the bracket that closes it
+var closers = map[token.TokenType]token.TokenType{
+	token.LPAREN:   token.RPAREN,
+	token.LBRACE:   token.RBRACE,
+	token.LBRACKET: token.RBRACKET,
+}
+
+// continuesLine holds the tokens that cannot end a statement, so input ending in one of them must carry on to the
+// next line
+var continuesLine = map[token.TokenType]bool{
+	token.ASSIGN:      true,
+	token.PLUS:        true,
+	token.MINUS:       true,
+	token.ASTERISK:    true,
+	token.SLASH:       true,
+	token.PERCENT:     true,
+	token.BANG:        true,
+	token.LESSTHAN:    true,
+	token.GREATERTHAN: true,
+	token.LT_EQ:       true,
+	token.GT_EQ:       true,
+	token.EQ:          true,
+	token.NOT_EQ:      true,
+	token.AND:         true,
+	token.OR:          true,
+	token.COMMA:       true,
+	token.COLON:       true,
+}
+
+// isComplete reports whether input can be handed to the parser, or whether the repl should keep reading lines because
+// a bracket, string or block comment is still open or the input ends with an operator.  Input that is wrong rather
+// than unfinished, such as a bracket closed by the wrong kind of bracket, counts as complete so the parser gets to
+// report it.
+func isComplete(input string) bool {
+	var lex = lexer.New(input)
+	var open []token.TokenType
+	var last token.Token
+
+	for tok := lex.NextToken(); tok.Type != token.EOF; tok = lex.NextToken() {
+		last = tok
+
+		if closer, ok := closers[tok.Type]; ok {
+			open = append(open, closer)
+			continue
+		}
+
+		switch tok.Type {
+		case token.RPAREN, token.RBRACE, token.RBRACKET:
+			if len(open) == 0 || open[len(open)-1] != tok.Type {
+				return true
+			}
+
+			open = open[:len(open)-1]
+		}
+	}
+
+	for _, diag := range lex.Errors() {
+		if diag.Code == diagnostic.UNTERMINATED_STRING || diag.Code == diagnostic.UNTERMINATED_COMMENT {
+			return false
+		}
+	}
+
+	return len(open) == 0 && !continuesLine[last.Type]
+}
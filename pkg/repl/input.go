@@ -0,0 +1,97 @@
+package repl
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"monkeyInterpreter/pkg/evaluator"
+	"monkeyInterpreter/pkg/highlight"
+	"monkeyInterpreter/pkg/lineedit"
+	"monkeyInterpreter/pkg/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// HISTORY_FILE is where lines entered at a terminal are saved, relative to the user's home directory
+const HISTORY_FILE = ".monkey_history"
+
+// lineReader shows a prompt and reads the next line of input, without its line ending
+type lineReader interface {
+	ReadLine(prompt string) (string, error)
+}
+
+// scanReader reads plain lines, for input that isn't a terminal such as a pipe or a file
+type scanReader struct {
+	scanner *bufio.Scanner
+	out     io.Writer
+}
+
+func (s *scanReader) ReadLine(prompt string) (string, error) {
+	fmt.Fprint(s.out, prompt)
+
+	if !s.scanner.Scan() {
+		if err := s.scanner.Err(); err != nil {
+			return "", err
+		}
+
+		return "", io.EOF
+	}
+
+	return s.scanner.Text(), nil
+}
+
+// newLineReader returns a line editor when in is a terminal, and otherwise reads plain lines so that piped input
+// behaves exactly as it is written
+func (r *Repl) newLineReader(in io.Reader, out io.Writer) lineReader {
+	file, ok := in.(*os.File)
+
+	if !ok || !lineedit.IsTerminal(int(file.Fd())) {
+		return &scanReader{scanner: bufio.NewScanner(in), out: out}
+	}
+
+	var editor = lineedit.NewTerminal(file, out)
+	editor.Complete = r.complete
+
+	if highlight.ColourEnabled(out) {
+		editor.Highlight = highlight.ANSI
+	}
+
+	if home, err := os.UserHomeDir(); err == nil {
+		if history, err := lineedit.LoadHistory(filepath.Join(home, HISTORY_FILE)); err == nil {
+			editor.SetHistory(history)
+		}
+	}
+
+	return editor
+}
+
+// complete returns the keywords, builtins, bindings in scope and meta commands that start with word
+func (r *Repl) complete(word string) []string {
+	var words []string
+
+	if strings.HasPrefix(word, ":") {
+		for _, cmd := range metaCommands {
+			words = append(words, ":"+cmd.name)
+		}
+	} else {
+		words = append(words, token.Keywords()...)
+		words = append(words, evaluator.BuiltinNames()...)
+		words = append(words, r.env.Names()...)
+	}
+
+	var matches []string
+	var seen = map[string]bool{}
+
+	for _, candidate := range words {
+		if strings.HasPrefix(candidate, word) && !seen[candidate] {
+			seen[candidate] = true
+			matches = append(matches, candidate)
+		}
+	}
+
+	sort.Strings(matches)
+
+	return matches
+}
@@ -1,32 +1,200 @@
 package repl
 
 import (
-	"bufio"
 	"fmt"
 	"io"
+	"monkeyInterpreter/pkg/ast"
+	"monkeyInterpreter/pkg/diagnostic"
+	"monkeyInterpreter/pkg/evaluator"
 	"monkeyInterpreter/pkg/lexer"
+	"monkeyInterpreter/pkg/lineedit"
+	"monkeyInterpreter/pkg/object"
+	"monkeyInterpreter/pkg/parser"
 	"monkeyInterpreter/pkg/token"
+	"strings"
+	"time"
 )
 
 const PROMPT = ">> "
 
+// CONTINUATION_PROMPT is shown instead of PROMPT while reading the rest of an unfinished statement
+const CONTINUATION_PROMPT = ".. "
+
+// Mode decides what the repl does with each line it reads
+type Mode string
+
+const (
+	// MODE_EVAL evaluates each line and prints the value it produces
+	MODE_EVAL Mode = "eval"
+
+	// MODE_TOKENS prints the tokens of each line without parsing it
+	MODE_TOKENS Mode = "tokens"
+
+	// MODE_AST prints the syntax tree of each line without evaluating it
+	MODE_AST Mode = "ast"
+)
+
+type Repl struct {
+	in   lineReader
+	out  io.Writer
+	mode Mode
+
+	// env persists between lines, so bindings made on one line can be used on the next
+	env *object.Environment
+
+	// timing is set when the time taken to parse and evaluate each input should be reported
+	timing bool
+
+	// quit is set once the session should end, even though there may be more input to read
+	quit bool
+}
+
+// New creates a repl reading from in.  When in is a terminal lines can be edited, recalled from the history and
+// completed with tab, otherwise they are read as they are.
+func New(in io.Reader, out io.Writer) *Repl {
+	var r = &Repl{out: out, mode: MODE_EVAL}
+	r.env = r.newEnvironment()
+	r.in = r.newLineReader(in, out)
+
+	return r
+}
+
+// newEnvironment returns an empty environment whose programs write to the repl's output
+func (r *Repl) newEnvironment() *object.Environment {
+	var env = object.NewEnvironment()
+	env.SetOutput(r.out)
+
+	return env
+}
+
+// Start runs a repl that evaluates every line read from in until in is exhausted
 func Start(in io.Reader, out io.Writer) {
-	var scanner = bufio.NewScanner(in)
+	New(in, out).Run()
+}
+
+func (r *Repl) SetMode(mode Mode) {
+	r.mode = mode
+}
+
+// Run reads input until in is exhausted.  Lines are collected until they form complete statements, so a function can
+// be typed or pasted over as many lines as it needs.
+func (r *Repl) Run() {
+	var input strings.Builder
 
 	for {
-		fmt.Fprintf(out, PROMPT)
+		var prompt = PROMPT
+
+		if input.Len() != 0 {
+			prompt = CONTINUATION_PROMPT
+		}
 
-		var scanned = scanner.Scan()
+		line, err := r.in.ReadLine(prompt)
+
+		// Ctrl-C abandons whatever has been typed so far, including the earlier lines of an unfinished statement
+		if err == lineedit.ErrInterrupted {
+			input.Reset()
+			continue
+		}
+
+		if err != nil {
+			// whatever is left over is handled anyway, so the parser can explain what is missing
+			if input.Len() != 0 {
+				fmt.Fprintln(r.out)
+				r.handle(input.String())
+			}
 
-		if !scanned {
 			return
 		}
 
-		var line = scanner.Text()
-		var lex = lexer.New(line)
+		// meta commands always fit on a single line, and aren't Monkey code so isComplete can't judge them
+		if input.Len() == 0 && isMetaCommand(line) {
+			r.runMetaCommand(line)
+
+			if r.quit {
+				return
+			}
+
+			continue
+		}
+
+		input.WriteString(line)
 
-		for tok := lex.NextToken(); tok.Type != token.EOF; tok = lex.NextToken() {
-			fmt.Fprintf(out, "%+v\n", tok)
+		if !isComplete(input.String()) {
+			input.WriteString("\n")
+			continue
 		}
+
+		r.handle(input.String())
+		input.Reset()
+	}
+}
+
+// handle processes a complete piece of input according to the current mode
+func (r *Repl) handle(input string) {
+	switch r.mode {
+	case MODE_TOKENS:
+		r.printTokens(input)
+	case MODE_AST:
+		r.printAst(input)
+	default:
+		r.eval("", input)
+	}
+}
+
+func (r *Repl) printTokens(input string) {
+	var lex = lexer.New(input)
+
+	for tok := lex.NextToken(); tok.Type != token.EOF; tok = lex.NextToken() {
+		fmt.Fprintf(r.out, "%s\t%s\t%q\n", tok.Pos, tok.Type, tok.Literal)
+	}
+}
+
+func (r *Repl) printAst(input string) {
+	var program = r.parse("", input)
+
+	if program == nil {
+		return
+	}
+
+	for _, statement := range program.Statements {
+		fmt.Fprintln(r.out, statement.String())
+	}
+}
+
+// parse parses input, printing its errors if it has any.  The program is nil when there were errors.
+func (r *Repl) parse(filename string, input string) *ast.Program {
+	var start = time.Now()
+	var parsr = parser.New(lexer.NewFile(filename, input))
+	var program = parsr.ParseProgram()
+
+	if r.timing {
+		fmt.Fprintf(r.out, "parse: %s\n", time.Since(start))
+	}
+
+	if len(parsr.Errors()) != 0 {
+		diagnostic.RenderAll(r.out, input, parsr.Errors())
+		return nil
+	}
+
+	return program
+}
+
+func (r *Repl) eval(filename string, input string) {
+	var program = r.parse(filename, input)
+
+	if program == nil {
+		return
+	}
+
+	var start = time.Now()
+	var evaluated = evaluator.Eval(program, r.env)
+
+	if r.timing {
+		fmt.Fprintf(r.out, "eval: %s\n", time.Since(start))
+	}
+
+	// let statements and empty lines produce no value, so there is nothing to show for them
+	if evaluated != nil {
+		fmt.Fprintln(r.out, evaluated.Inspect())
 	}
 }
@@ -0,0 +1,36 @@
+package token
+
+import "fmt"
+
+// Position describes a location in the source.  Line and Column are 1-based, Offset is the 0-based byte offset from
+// the start of the input.  A Position with a Line of 0 is considered invalid, and is used for nodes that were built by
+// hand rather than produced by the lexer.
+type Position struct {
+	Filename string `json:"filename,omitempty"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Offset   int    `json:"offset"`
+}
+
+func (pos Position) IsValid() bool {
+	return pos.Line > 0
+}
+
+// String formats the position as file:line:column, leaving out the parts that are not known
+func (pos Position) String() string {
+	var out = pos.Filename
+
+	if pos.IsValid() {
+		if out != "" {
+			out += ":"
+		}
+
+		out += fmt.Sprintf("%d:%d", pos.Line, pos.Column)
+	}
+
+	if out == "" {
+		out = "-"
+	}
+
+	return out
+}
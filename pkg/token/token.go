@@ -3,18 +3,39 @@ package token
 type TokenType string
 
 type Token struct {
-	Type    TokenType
-	Literal string
+	Type    TokenType `json:"type"`
+	Literal string    `json:"literal"`
+
+	// Pos is the position of the first character of the token in the source
+	Pos Position `json:"pos"`
+
+	// End is the position immediately after the last character of the token
+	End Position `json:"end"`
+
+	// Leading, Raw and Trailing are only filled in by a lexer in lossless mode.  Raw is the token exactly as it was
+	// written, which for a string differs from its Literal.  Trailing is the whitespace and comments after the token up
+	// to the end of its line, and Leading is everything between the previous token's Trailing and this token, so that
+	// writing out Leading, Raw and Trailing for every token in turn gives back the source unchanged.
+	Leading  string `json:"leading,omitempty"`
+	Raw      string `json:"raw,omitempty"`
+	Trailing string `json:"trailing,omitempty"`
 }
 
 const (
 	ILLEGAL = "ILLEGAL"
 	EOF     = "EOF"
 
+	// comments are only produced when the lexer is asked for them, DOC_COMMENT being one that starts /// or /**
+
+	COMMENT     = "COMMENT"
+	DOC_COMMENT = "DOC_COMMENT"
+
 	// identifiers and literals
 
-	IDENT = "IDENT"
-	INT   = "INT"
+	IDENT  = "IDENT"
+	INT    = "INT"
+	FLOAT  = "FLOAT"
+	STRING = "STRING"
 
 	// Operators
 
@@ -23,23 +44,33 @@ const (
 	MINUS    = "-"
 	ASTERISK = "*"
 	SLASH    = "/"
+	PERCENT  = "%"
 	BANG     = "!"
 
 	LESSTHAN    = "<"
 	GREATERTHAN = ">"
+	LT_EQ       = "<="
+	GT_EQ       = ">="
 	EQ          = "=="
 	NOT_EQ      = "!="
 
+	AND = "&&"
+	OR  = "||"
+
 	// Delimiters
 
 	COMMA     = ","
 	SEMICOLON = ";"
+	COLON     = ":"
 
 	LPAREN = "("
 	RPAREN = ")"
 	LBRACE = "{"
 	RBRACE = "}"
 
+	LBRACKET = "["
+	RBRACKET = "]"
+
 	// Keywords
 
 	FUNCTION = "FUNCTION"
@@ -51,7 +82,7 @@ const (
 	FALSE    = "FALSE"
 )
 
-func NewToken(tokenType TokenType, ch byte) Token {
+func NewToken(tokenType TokenType, ch rune) Token {
 	return Token{Type: tokenType, Literal: string(ch)}
 }
 
@@ -65,6 +96,17 @@ var keywords = map[string]TokenType{
 	"false":  FALSE,
 }
 
+// Keywords returns every keyword, in no particular order
+func Keywords() []string {
+	var words = make([]string, 0, len(keywords))
+
+	for word := range keywords {
+		words = append(words, word)
+	}
+
+	return words
+}
+
 func LookUpIdent(ident string) TokenType {
 	if tok, ok := keywords[ident]; ok {
 		return tok
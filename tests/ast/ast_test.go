@@ -1,8 +1,19 @@
 package ast
 
 import (
+	"encoding/json"
+	"fmt"
+	goast "go/ast"
+	"go/parser"
+	gotoken "go/token"
 	"monkeyInterpreter/pkg/ast"
+	"monkeyInterpreter/pkg/lexer"
+	monkeyparser "monkeyInterpreter/pkg/parser"
 	"monkeyInterpreter/pkg/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -27,3 +38,536 @@ func TestString(t *testing.T) {
 		t.Errorf("program.String() was wrong, got=%q", program.String())
 	}
 }
+
+// everyNodeSource holds at least one of every kind of node
+const everyNodeSource = `let a = [1, 2.5, "s", true];
+return -a[0];
+if (a) { fn(x) { x + 1 }(2) } else { {"k": 1} }`
+
+// nodeTypes returns the name of every type in the ast package that implements Node, read from its source so that a
+// new node type can't be added without the walk being tested on it
+func nodeTypes(t *testing.T) map[string]bool {
+	const dir = "../../pkg/ast"
+
+	entries, err := os.ReadDir(dir)
+
+	if err != nil {
+		t.Fatalf("could not read the ast package: %s", err)
+	}
+
+	var fset = gotoken.NewFileSet()
+	var types = map[string]bool{}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") || strings.HasSuffix(entry.Name(), "_test.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, filepath.Join(dir, entry.Name()), nil, 0)
+
+		if err != nil {
+			t.Fatalf("could not read the ast package: %s", err)
+		}
+
+		for _, decl := range file.Decls {
+			fun, ok := decl.(*goast.FuncDecl)
+
+			if !ok || fun.Recv == nil || fun.Name.Name != "TokenLiteral" {
+				continue
+			}
+
+			if star, ok := fun.Recv.List[0].Type.(*goast.StarExpr); ok {
+				types[star.X.(*goast.Ident).Name] = true
+			}
+		}
+	}
+
+	return types
+}
+
+func TestInspectVisitsEveryNodeType(t *testing.T) {
+	program := parse(t, everyNodeSource)
+	visited := map[string]bool{}
+
+	ast.Inspect(program, func(node ast.Node) bool {
+		if node != nil {
+			visited[strings.TrimPrefix(fmt.Sprintf("%T", node), "*ast.")] = true
+		}
+
+		return true
+	})
+
+	for name := range nodeTypes(t) {
+		if !visited[name] {
+			t.Errorf("expected a %s to be visited", name)
+		}
+	}
+
+	for name := range visited {
+		if !nodeTypes(t)[name] {
+			t.Errorf("visited %s, which is not a node type", name)
+		}
+	}
+}
+
+func TestWalkOrder(t *testing.T) {
+	program := parse(t, "let f = fn(a, b) { a * b }; f(1, -2)[0]")
+
+	var visited []string
+	var depth = 0
+
+	ast.Inspect(program, func(node ast.Node) bool {
+		if node == nil {
+			depth -= 1
+			return false
+		}
+
+		visited = append(visited, strings.Repeat(".", depth)+strings.TrimPrefix(fmt.Sprintf("%T", node), "*ast."))
+		depth += 1
+
+		return true
+	})
+
+	expected := []string{
+		"Program",
+		".LetStatement",
+		"..Identifier",
+		"..FunctionLiteral",
+		"...Identifier",
+		"...Identifier",
+		"...BlockStatement",
+		"....ExpressionStatement",
+		".....InfixExpression",
+		"......Identifier",
+		"......Identifier",
+		".ExpressionStatement",
+		"..IndexExpression",
+		"...CallExpression",
+		"....Identifier",
+		"....IntegerLiteral",
+		"....PrefixExpression",
+		".....IntegerLiteral",
+		"...IntegerLiteral",
+	}
+
+	if strings.Join(visited, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected=\n%s\ngot=\n%s", strings.Join(expected, "\n"), strings.Join(visited, "\n"))
+	}
+
+	if depth != 0 {
+		t.Errorf("expected every node to be closed by a call with nil, depth=%d", depth)
+	}
+}
+
+func TestInspectSkipsChildren(t *testing.T) {
+	program := parse(t, "let x = fn() { 1 + 2 }; 3")
+
+	var integers []string
+
+	ast.Inspect(program, func(node ast.Node) bool {
+		if _, ok := node.(*ast.FunctionLiteral); ok {
+			return false
+		}
+
+		if integer, ok := node.(*ast.IntegerLiteral); ok {
+			integers = append(integers, integer.String())
+		}
+
+		return true
+	})
+
+	if fmt.Sprint(integers) != "[3]" {
+		t.Errorf("expected only the integer outside the function, got=%v", integers)
+	}
+}
+
+// counter is a Visitor that counts the nodes it visits, stopping below the depth it is given
+type counter struct {
+	count *int
+	depth int
+}
+
+func (c counter) Visit(node ast.Node) ast.Visitor {
+	if node == nil || c.depth == 0 {
+		return nil
+	}
+
+	*c.count += 1
+
+	return counter{count: c.count, depth: c.depth - 1}
+}
+
+func TestWalkWithVisitor(t *testing.T) {
+	program := parse(t, "1 + 2 * 3")
+
+	for depth, expected := range []int{0, 1, 2, 3, 5, 7, 7} {
+		var count = 0
+
+		ast.Walk(counter{count: &count, depth: depth}, program)
+
+		if count != expected {
+			t.Errorf("depth %d - expected=%d, got=%d", depth, expected, count)
+		}
+	}
+}
+
+func TestWalkSkipsMissingParts(t *testing.T) {
+	program := &ast.Program{Statements: []ast.Statement{
+		&ast.LetStatement{Name: &ast.Identifier{Value: "x"}},
+		&ast.ReturnStatement{},
+		&ast.ExpressionStatement{Expression: &ast.IfExpression{Consequence: &ast.BlockStatement{}}},
+		&ast.ExpressionStatement{Expression: &ast.FunctionLiteral{}},
+	}}
+
+	var count = 0
+
+	ast.Inspect(program, func(node ast.Node) bool {
+		if node != nil {
+			count += 1
+		}
+
+		return true
+	})
+
+	if count != 9 {
+		t.Errorf("expected the nodes that are there to be visited, got=%d", count)
+	}
+}
+
+func TestWalkPanicsOnUnknownNode(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected a panic for a node type Walk doesn't know")
+		}
+	}()
+
+	ast.Inspect(&ast.ExpressionStatement{Expression: unknownNode{}}, func(ast.Node) bool { return true })
+}
+
+type unknownNode struct{ ast.Expression }
+
+func parse(t *testing.T, input string) *ast.Program {
+	t.Helper()
+
+	p := monkeyparser.New(lexer.New(input))
+	program := p.ParseProgram()
+
+	if len(p.Errors()) != 0 {
+		t.Fatalf("unexpected errors: %v", p.Errors())
+	}
+
+	return program
+}
+
+func TestModifyFoldsConstants(t *testing.T) {
+	program := parse(t, "let x = 1 + 2 * 3; x * (4 - 1)")
+
+	fold := func(node ast.Node) ast.Node {
+		infix, ok := node.(*ast.InfixExpression)
+
+		if !ok {
+			return node
+		}
+
+		left, leftOk := infix.Left.(*ast.IntegerLiteral)
+		right, rightOk := infix.Right.(*ast.IntegerLiteral)
+
+		if !leftOk || !rightOk {
+			return node
+		}
+
+		var value int64
+
+		switch infix.Operator {
+		case "+":
+			value = left.Value + right.Value
+		case "-":
+			value = left.Value - right.Value
+		case "*":
+			value = left.Value * right.Value
+		default:
+			return node
+		}
+
+		literal := fmt.Sprint(value)
+
+		return &ast.IntegerLiteral{Token: token.Token{Type: token.INT, Literal: literal}, Value: value}
+	}
+
+	result := ast.Modify(program, fold)
+
+	if result != program {
+		t.Errorf("expected the program itself back")
+	}
+
+	if program.String() != "let x = 7;(x * 3)" {
+		t.Errorf("expected the constants folded, got=%q", program.String())
+	}
+}
+
+func TestModifyReachesEveryNode(t *testing.T) {
+	program := parse(t, everyNodeSource)
+
+	// the every-node source has its own nodes, which must each be handed to the modifier once
+	var expected = 0
+
+	ast.Inspect(program, func(node ast.Node) bool {
+		if node != nil {
+			expected += 1
+		}
+
+		return true
+	})
+
+	var seen = map[ast.Node]int{}
+
+	ast.Modify(program, func(node ast.Node) ast.Node {
+		seen[node] += 1
+
+		if integer, ok := node.(*ast.IntegerLiteral); ok {
+			return &ast.IntegerLiteral{Token: integer.Token, Value: integer.Value * 10}
+		}
+
+		return node
+	})
+
+	if len(seen) != expected {
+		t.Errorf("expected %d nodes to be modified, got=%d", expected, len(seen))
+	}
+
+	for node, count := range seen {
+		if count != 1 {
+			t.Errorf("expected %s to be modified once, got=%d", node, count)
+		}
+	}
+
+	var integers []int64
+
+	ast.Inspect(program, func(node ast.Node) bool {
+		if integer, ok := node.(*ast.IntegerLiteral); ok {
+			integers = append(integers, integer.Value)
+		}
+
+		return true
+	})
+
+	if fmt.Sprint(integers) != "[10 0 10 20 10]" {
+		t.Errorf("expected every integer to be replaced, got=%v", integers)
+	}
+}
+
+func TestModifyRejectsMisplacedNode(t *testing.T) {
+	program := parse(t, "1 + 2")
+
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(fmt.Sprint(r), "cannot replace") {
+			t.Errorf("expected a panic about the replacement, got=%v", r)
+		}
+	}()
+
+	ast.Modify(program, func(node ast.Node) ast.Node {
+		if _, ok := node.(*ast.IntegerLiteral); ok {
+			return &ast.ReturnStatement{}
+		}
+
+		return node
+	})
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	inputs := []string{
+		everyNodeSource,
+		"let f = fn() { return; }; f()",
+		"{}; []; if (true) { }",
+	}
+
+	for _, input := range inputs {
+		p := monkeyparser.New(lexer.NewFile("fixture.mk", input))
+		program := p.ParseProgram()
+
+		data, err := ast.MarshalJSON(program)
+
+		if err != nil {
+			t.Fatalf("could not marshal %q: %s", input, err)
+		}
+
+		node, err := ast.UnmarshalJSON(data)
+
+		if err != nil {
+			t.Fatalf("could not unmarshal %s: %s", data, err)
+		}
+
+		if !reflect.DeepEqual(node, program) {
+			t.Errorf("expected the same tree back for %q, got=%s", input, node)
+		}
+
+		// encoding/json can read straight into a node of a known type
+		var decoded ast.Program
+
+		if err := json.Unmarshal(data, &decoded); err != nil || !reflect.DeepEqual(&decoded, program) {
+			t.Errorf("expected json.Unmarshal to give the same tree back for %q, err=%v", input, err)
+		}
+	}
+}
+
+func TestJSONRoundTripsDeepTrees(t *testing.T) {
+	// encoding/json reads no more than 10000 levels deep
+	inputs := []string{
+		strings.Repeat("x + ", 8000) + "x",
+		strings.Repeat("-", 8000) + "x",
+		strings.Repeat("[", 4000) + strings.Repeat("]", 4000),
+	}
+
+	for _, input := range inputs {
+		program := parse(t, input)
+
+		data, err := ast.MarshalJSON(program)
+
+		if err != nil {
+			t.Fatalf("could not marshal %.20q: %s", input, err)
+		}
+
+		node, err := ast.UnmarshalJSON(data)
+
+		if err != nil || !reflect.DeepEqual(node, program) {
+			t.Errorf("expected the same tree back for %.20q, err=%v", input, err)
+		}
+	}
+}
+
+func TestJSONSpans(t *testing.T) {
+	ast.Inspect(parse(t, everyNodeSource), func(node ast.Node) bool {
+		if node == nil {
+			return false
+		}
+
+		data, err := ast.MarshalJSON(node)
+
+		if err != nil {
+			t.Fatalf("could not marshal %s: %s", node, err)
+		}
+
+		var span struct {
+			Pos token.Position `json:"pos"`
+			End token.Position `json:"end"`
+		}
+
+		if err := json.Unmarshal(data, &span); err != nil {
+			t.Fatalf("could not read the span of %s: %s", node, err)
+		}
+
+		if span.Pos != node.Pos() || span.End != node.End() {
+			t.Errorf("expected %T to span %s-%s, got=%s-%s", node, node.Pos(), node.End(), span.Pos, span.End)
+		}
+
+		return true
+	})
+}
+
+func TestJSONKeepsTrivia(t *testing.T) {
+	lex := lexer.New("  x // the x\n")
+	lex.SetMode(lexer.SCAN_TRIVIA)
+
+	program := monkeyparser.New(lex).ParseProgram()
+	data, _ := json.Marshal(program)
+	node, err := ast.UnmarshalJSON(data)
+
+	if err != nil || !reflect.DeepEqual(node, program) {
+		t.Fatalf("expected the same tree back, err=%v", err)
+	}
+
+	ident := node.(*ast.Program).Statements[0].(*ast.ExpressionStatement).Expression.(*ast.Identifier)
+
+	if ident.Token.Leading != "  " || ident.Token.Raw != "x" || ident.Token.Trailing != " // the x" {
+		t.Errorf("expected the trivia of x to be kept, got=%+v", ident.Token)
+	}
+}
+
+func TestJSONForm(t *testing.T) {
+	program := parse(t, "-a")
+
+	data, err := ast.MarshalJSON(program.Statements[0].(*ast.ExpressionStatement).Expression)
+
+	if err != nil {
+		t.Fatalf("could not marshal: %s", err)
+	}
+
+	expected := `{"type":"PrefixExpression",` +
+		`"pos":{"line":1,"column":1,"offset":0},"end":{"line":1,"column":3,"offset":2},` +
+		`"token":{"type":"-","literal":"-",` +
+		`"pos":{"line":1,"column":1,"offset":0},"end":{"line":1,"column":2,"offset":1}},` +
+		`"operator":"-",` +
+		`"right":{"type":"Identifier",` +
+		`"pos":{"line":1,"column":2,"offset":1},"end":{"line":1,"column":3,"offset":2},` +
+		`"token":{"type":"IDENT","literal":"a",` +
+		`"pos":{"line":1,"column":2,"offset":1},"end":{"line":1,"column":3,"offset":2}},` +
+		`"value":"a"}}`
+
+	if string(data) != expected {
+		t.Errorf("expected=\n%s\ngot=\n%s", expected, data)
+	}
+}
+
+func TestJSONMissingParts(t *testing.T) {
+	program := &ast.Program{Statements: []ast.Statement{
+		&ast.LetStatement{Name: &ast.Identifier{Value: "x"}},
+		&ast.ExpressionStatement{Expression: &ast.IfExpression{Consequence: &ast.BlockStatement{}}},
+		&ast.ExpressionStatement{Expression: &ast.HashLiteral{Pairs: []ast.HashPair{}}},
+	}}
+
+	data, err := ast.MarshalJSON(program)
+
+	if err != nil {
+		t.Fatalf("could not marshal: %s", err)
+	}
+
+	node, err := ast.UnmarshalJSON(data)
+
+	if err != nil || !reflect.DeepEqual(node, program) {
+		t.Errorf("expected the same tree back, err=%v, got=%#v", err, node)
+	}
+
+	if data, _ := ast.MarshalJSON(nil); string(data) != "null" {
+		t.Errorf("expected a missing node to be null, got=%s", data)
+	}
+}
+
+func TestJSONCoversEveryNodeType(t *testing.T) {
+	for name := range nodeTypes(t) {
+		data := fmt.Sprintf(`{"type":%q}`, name)
+		node, err := ast.UnmarshalJSON([]byte(data))
+
+		if err != nil {
+			t.Errorf("expected a %s to be read, got error %s", name, err)
+			continue
+		}
+
+		if got := strings.TrimPrefix(fmt.Sprintf("%T", node), "*ast."); got != name {
+			t.Errorf("expected a %s, got=%s", name, got)
+		}
+	}
+}
+
+func TestJSONErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{"type":"Mystery"}`, `ast: unknown node type "Mystery"`},
+		{
+			`{"type":"ExpressionStatement","expression":{"type":"ReturnStatement"}}`,
+			"ast: expected Expression, got ReturnStatement",
+		},
+		{`{"type":"Program","statements":[{"type":"Identifier"}]}`, "ast: expected Statement, got Identifier"},
+		{`{"type":"LetStatement","name":{"type":"IntegerLiteral"}}`, "ast: expected Identifier, got IntegerLiteral"},
+		{`{"type":"Program","statements":[{"type":`, "unexpected EOF"},
+	}
+
+	for i, tt := range tests {
+		_, err := ast.UnmarshalJSON([]byte(tt.input))
+
+		if err == nil || err.Error() != tt.expected {
+			t.Errorf("tests[%d] - expected error %q, got=%v", i, tt.expected, err)
+		}
+	}
+}
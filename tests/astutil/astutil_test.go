@@ -0,0 +1,338 @@
+package astutil
+
+import (
+	"fmt"
+	"monkeyInterpreter/pkg/ast"
+	"monkeyInterpreter/pkg/ast/astutil"
+	"monkeyInterpreter/pkg/lexer"
+	"monkeyInterpreter/pkg/parser"
+	"monkeyInterpreter/pkg/token"
+	"strings"
+	"testing"
+)
+
+func parse(t *testing.T, input string) *ast.Program {
+	t.Helper()
+
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+
+	if len(p.Errors()) != 0 {
+		t.Fatalf("unexpected errors: %v", p.Errors())
+	}
+
+	return program
+}
+
+func identifier(name string) *ast.Identifier {
+	return &ast.Identifier{Token: token.Token{Type: token.IDENT, Literal: name}, Value: name}
+}
+
+func statement(expr ast.Expression) *ast.ExpressionStatement {
+	return &ast.ExpressionStatement{Token: expr.(*ast.Identifier).Token, Expression: expr}
+}
+
+func TestCursorDescribesPosition(t *testing.T) {
+	program := parse(t, "let x = f(a, b);")
+
+	var visited []string
+
+	astutil.Apply(program, func(c *astutil.Cursor) bool {
+		visited = append(visited, fmt.Sprintf("%T %s %d", c.Parent(), c.Name(), c.Index()))
+		return true
+	}, nil)
+
+	expected := []string{
+		"<nil>  -1",
+		"*ast.Program Statements 0",
+		"*ast.LetStatement Name -1",
+		"*ast.LetStatement Value -1",
+		"*ast.CallExpression Function -1",
+		"*ast.CallExpression Arguments 0",
+		"*ast.CallExpression Arguments 1",
+	}
+
+	if strings.Join(visited, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected=\n%s\ngot=\n%s", strings.Join(expected, "\n"), strings.Join(visited, "\n"))
+	}
+}
+
+func TestReplace(t *testing.T) {
+	program := parse(t, "let y = old + f(old); fn(old) { old }")
+
+	// a let's name and a function's parameters are replaced like any other identifier
+	astutil.Apply(program, func(c *astutil.Cursor) bool {
+		if ident, ok := c.Node().(*ast.Identifier); ok && ident.Value == "old" {
+			c.Replace(identifier("renamed"))
+		}
+
+		return true
+	}, nil)
+
+	if program.String() != "let y = (renamed + f(renamed));fn(renamed) renamed" {
+		t.Errorf("expected every old to be renamed, got=%q", program.String())
+	}
+}
+
+func TestReplaceVisitsNewChildren(t *testing.T) {
+	program := parse(t, "a")
+
+	var visited []string
+
+	astutil.Apply(program, func(c *astutil.Cursor) bool {
+		if ident, ok := c.Node().(*ast.Identifier); ok {
+			visited = append(visited, ident.Value)
+
+			if ident.Value == "a" {
+				c.Replace(&ast.PrefixExpression{Operator: "-", Right: identifier("b")})
+			}
+		}
+
+		return true
+	}, func(c *astutil.Cursor) bool {
+		if _, ok := c.Node().(*ast.PrefixExpression); ok {
+			visited = append(visited, "post -")
+		}
+
+		return true
+	})
+
+	if fmt.Sprint(visited) != "[a b post -]" {
+		t.Errorf("expected the replacement and its children to be visited, got=%v", visited)
+	}
+
+	if program.String() != "(-b)" {
+		t.Errorf("expected the replacement in the tree, got=%q", program.String())
+	}
+}
+
+func TestReplaceRoot(t *testing.T) {
+	program := parse(t, "a")
+
+	replacement := &ast.Program{}
+	result := astutil.Apply(program, func(c *astutil.Cursor) bool {
+		if c.Parent() == nil {
+			c.Replace(replacement)
+		}
+
+		return true
+	}, nil)
+
+	if result != replacement {
+		t.Errorf("expected the new root back, got=%v", result)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	program := parse(t, "debug(1); let x = 2; debug(x); if (x) { debug(3); x; debug(4) }; f(1, 2, 3)")
+
+	var visited []string
+
+	astutil.Apply(program, func(c *astutil.Cursor) bool {
+		if stmt, ok := c.Node().(*ast.ExpressionStatement); ok {
+			if call, ok := stmt.Expression.(*ast.CallExpression); ok && call.Function.String() == "debug" {
+				c.Delete()
+				return true
+			}
+		}
+
+		// the 2 goes from the list of arguments
+		if integer, ok := c.Node().(*ast.IntegerLiteral); ok && c.Name() == "Arguments" && integer.Value == 2 {
+			c.Delete()
+			return true
+		}
+
+		if ident, ok := c.Node().(*ast.Identifier); ok {
+			visited = append(visited, ident.Value)
+		}
+
+		return true
+	}, func(c *astutil.Cursor) bool {
+		if c.Node() == nil {
+			t.Errorf("expected post not to be called for a deleted node")
+		}
+
+		return true
+	})
+
+	if program.String() != "let x = 2;ifx xf(1, 3)" {
+		t.Errorf("expected the debug calls removed, got=%q", program.String())
+	}
+
+	if fmt.Sprint(visited) != "[x x x f]" {
+		t.Errorf("expected nothing inside a deleted statement to be visited, got=%v", visited)
+	}
+}
+
+func TestInsert(t *testing.T) {
+	program := parse(t, "a; if (b) { c }")
+
+	var visited []string
+
+	astutil.Apply(program, func(c *astutil.Cursor) bool {
+		ident, ok := c.Node().(*ast.Identifier)
+
+		if !ok {
+			return true
+		}
+
+		visited = append(visited, ident.Value)
+
+		return true
+	}, func(c *astutil.Cursor) bool {
+		stmt, ok := c.Node().(*ast.ExpressionStatement)
+
+		if !ok {
+			return true
+		}
+
+		if ident, ok := stmt.Expression.(*ast.Identifier); ok {
+			c.InsertBefore(statement(identifier("before_" + ident.Value)))
+			c.InsertAfter(statement(identifier("after_" + ident.Value)))
+		}
+
+		return true
+	})
+
+	if program.String() != "before_aaafter_aifb before_ccafter_c" {
+		t.Errorf("expected statements around each identifier statement, got=%q", program.String())
+	}
+
+	if fmt.Sprint(visited) != "[a b c]" {
+		t.Errorf("expected inserted nodes not to be visited, got=%v", visited)
+	}
+}
+
+func TestPreFalseSkipsChildren(t *testing.T) {
+	program := parse(t, "fn() { a }; b")
+
+	var visited []string
+
+	astutil.Apply(program, func(c *astutil.Cursor) bool {
+		if ident, ok := c.Node().(*ast.Identifier); ok {
+			visited = append(visited, ident.Value)
+		}
+
+		_, isFunction := c.Node().(*ast.FunctionLiteral)
+
+		return !isFunction
+	}, func(c *astutil.Cursor) bool {
+		if _, ok := c.Node().(*ast.FunctionLiteral); ok {
+			t.Errorf("expected post to be skipped along with the children")
+		}
+
+		return true
+	})
+
+	if fmt.Sprint(visited) != "[b]" {
+		t.Errorf("expected only the identifier outside the function, got=%v", visited)
+	}
+}
+
+func TestPostFalseStops(t *testing.T) {
+	program := parse(t, "a; b; c")
+
+	var visited []string
+
+	result := astutil.Apply(program, nil, func(c *astutil.Cursor) bool {
+		if ident, ok := c.Node().(*ast.Identifier); ok {
+			visited = append(visited, ident.Value)
+			return ident.Value != "b"
+		}
+
+		return true
+	})
+
+	if fmt.Sprint(visited) != "[a b]" {
+		t.Errorf("expected the traversal to stop after b, got=%v", visited)
+	}
+
+	if result != program {
+		t.Errorf("expected the root back after stopping")
+	}
+}
+
+func TestMisuse(t *testing.T) {
+	tests := []struct {
+		input    string
+		pre      astutil.ApplyFunc
+		expected string
+	}{
+		{
+			"1 + 2",
+			func(c *astutil.Cursor) bool {
+				if c.Name() == "Left" {
+					c.Delete()
+				}
+
+				return true
+			},
+			"Delete used on Left of *ast.InfixExpression, which is not a list",
+		},
+		{
+			"1",
+			func(c *astutil.Cursor) bool {
+				c.InsertAfter(&ast.Program{})
+				return true
+			},
+			"InsertAfter used on the root, which is not in a list",
+		},
+		{
+			"1 + 2",
+			func(c *astutil.Cursor) bool {
+				if c.Name() == "Right" {
+					c.Replace(&ast.LetStatement{})
+				}
+
+				return true
+			},
+			"*ast.LetStatement cannot replace *ast.IntegerLiteral in Right of *ast.InfixExpression",
+		},
+		{
+			"let x = 1",
+			func(c *astutil.Cursor) bool {
+				if c.Name() == "Name" {
+					c.Replace(&ast.IntegerLiteral{})
+				}
+
+				return true
+			},
+			"*ast.IntegerLiteral cannot replace *ast.Identifier in Name of *ast.LetStatement",
+		},
+		{
+			"1; 2",
+			func(c *astutil.Cursor) bool {
+				if c.Name() == "Statements" {
+					c.InsertBefore(&ast.IntegerLiteral{})
+				}
+
+				return true
+			},
+			"*ast.IntegerLiteral cannot go in Statements of *ast.Program",
+		},
+		{
+			"1; 2",
+			func(c *astutil.Cursor) bool {
+				if c.Name() == "Statements" {
+					c.Delete()
+					c.Replace(&ast.ExpressionStatement{})
+				}
+
+				return true
+			},
+			"Replace used after the node was deleted",
+		},
+	}
+
+	for i, tt := range tests {
+		func() {
+			defer func() {
+				if r := recover(); fmt.Sprint(r) != "astutil: "+tt.expected {
+					t.Errorf("tests[%d] - expected a panic with %q, got=%v", i, tt.expected, r)
+				}
+			}()
+
+			astutil.Apply(parse(t, tt.input), tt.pre, nil)
+		}()
+	}
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"bytes"
+	"monkeyInterpreter/pkg/cli"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCommands(t *testing.T) {
+	tests := []struct {
+		args           []string
+		stdin          string
+		expectedCode   int
+		expectedStdout string
+		expectedStderr string
+	}{
+		{[]string{"check", "-"}, "let x = 5;", cli.EXIT_OK, "", ""},
+		{[]string{"check", "-"}, "let x = ;", cli.EXIT_FAILURE, "", "<stdin>:1:9: error[P002]"},
+		{[]string{"ast", "-"}, "let x = 1 + 2 * 3; x", cli.EXIT_OK, "let x = (1 + (2 * 3));\nx\n", ""},
+		{[]string{"ast", "-"}, "let = 1", cli.EXIT_FAILURE, "", "expected next token to be IDENT"},
+		{[]string{"tokens", "-"}, "x;", cli.EXIT_OK, "<stdin>:1:1\tIDENT\t\"x\"\n<stdin>:1:2\t;\t\";\"\n<stdin>:1:3\tEOF\t\"\"\n", ""},
+		{[]string{"tokens", "-"}, `"abc`, cli.EXIT_FAILURE, "ILLEGAL", "string literal not terminated"},
+		{[]string{"run", "-"}, "let x = 1; x + 1", cli.EXIT_OK, "", ""},
+		{[]string{"run", "-"}, "puts(1)", cli.EXIT_OK, "1\n", ""},
+		{[]string{"run", "-"}, "1 + true", cli.EXIT_FAILURE, "", "runtime error: type mismatch: INTEGER + BOOLEAN"},
+		{[]string{"run", "-"}, "1 +", cli.EXIT_FAILURE, "", "error[P002]"},
+		{[]string{"help"}, "", cli.EXIT_OK, "Usage: monkey", ""},
+		{[]string{"bogus"}, "", cli.EXIT_USAGE, "", `unknown command "bogus"`},
+		{[]string{"run"}, "", cli.EXIT_USAGE, "", "run takes exactly one file"},
+		{[]string{"repl", "extra"}, "", cli.EXIT_USAGE, "", "repl takes no arguments other than --tokens"},
+		{[]string{"repl"}, "let a = 2;\na * 3\n", cli.EXIT_OK, ">> >> 6\n>> ", ""},
+		{[]string{"repl", "--tokens"}, "a\n", cli.EXIT_OK, ">> 1:1\tIDENT\t\"a\"\n>> ", ""},
+		{[]string{"highlight", "-"}, "let x = 1;", cli.EXIT_OK, "let x = 1;", ""},
+		{[]string{"highlight", "--format=html", "-"}, "x", cli.EXIT_OK, `<span class="mk-identifier">x</span>`, ""},
+		{[]string{"highlight", "-", "--format=svg"}, "", cli.EXIT_USAGE, "", `unknown format "svg"`},
+		{[]string{"highlight"}, "", cli.EXIT_USAGE, "", "highlight takes exactly one file"},
+		{[]string{"run", "does-not-exist.mk"}, "", cli.EXIT_USAGE, "", "does-not-exist.mk"},
+	}
+
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+
+		code := cli.Run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+
+		if code != tt.expectedCode {
+			t.Errorf("%v: expected exit code %d, got=%d (stderr %q)", tt.args, tt.expectedCode, code, stderr.String())
+		}
+
+		if !strings.Contains(stdout.String(), tt.expectedStdout) {
+			t.Errorf("%v: expected stdout to contain %q, got=%q", tt.args, tt.expectedStdout, stdout.String())
+		}
+
+		if tt.expectedStdout == "" && stdout.Len() != 0 {
+			t.Errorf("%v: expected no output, got=%q", tt.args, stdout.String())
+		}
+
+		if !strings.Contains(stderr.String(), tt.expectedStderr) {
+			t.Errorf("%v: expected stderr to contain %q, got=%q", tt.args, tt.expectedStderr, stderr.String())
+		}
+	}
+}
+
+func TestCheckFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "script.mk")
+
+	if err := os.WriteFile(path, []byte("let a = 1;\nlet b = (a;\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	code := cli.Run([]string{"check", path}, strings.NewReader(""), &stdout, &stderr)
+
+	if code != cli.EXIT_FAILURE {
+		t.Fatalf("expected exit code %d, got=%d", cli.EXIT_FAILURE, code)
+	}
+
+	expected := path + ":2:11: error[P001]: expected next token to be ), got ; instead\n" +
+		"   2 | let b = (a;\n" +
+		"     |           ^\n"
+
+	if stderr.String() != expected {
+		t.Errorf("expected=%q, got=%q", expected, stderr.String())
+	}
+}
@@ -0,0 +1,125 @@
+package cst
+
+import (
+	"monkeyInterpreter/pkg/ast"
+	"monkeyInterpreter/pkg/cst"
+	"monkeyInterpreter/pkg/token"
+	"strings"
+	"testing"
+)
+
+func TestPrintsSourceUnchanged(t *testing.T) {
+	inputs := []string{
+		"",
+		"\n\n// only a comment\n",
+		"let add = fn(a,b){ a+b };   // adds\n\n/// doc\nadd( 1 ,\t2 )\n",
+		"let x = (1 + 2) * -3;\r\nif (x >= 0) { x } else { [x, {\"k\": x}][0] }",
+		"\uFEFFlet s = \"tab\\there\";",
+		"let = 5; let y 6; ) x + ;\n  z",
+		"\"never closed\n let x = 1",
+		"let a = 1 /* unterminated",
+		"a \x00 \xff b",
+	}
+
+	for _, input := range inputs {
+		root, _ := cst.Parse("", input)
+
+		if got := root.Text(); got != input {
+			t.Errorf("expected=%q, got=%q", input, got)
+		}
+	}
+}
+
+func TestTooManyErrorsStillKeepsSource(t *testing.T) {
+	input := strings.Repeat(") ", 200) + "let x = 1; // the end\n"
+
+	root, errs := cst.Parse("", input)
+
+	if len(errs) == 0 {
+		t.Fatalf("expected errors")
+	}
+
+	if got := root.Text(); got != input {
+		t.Errorf("expected the whole input back, got=%q", got)
+	}
+}
+
+func TestTreeShape(t *testing.T) {
+	root, errs := cst.Parse("", "let x = a + 1; // sum\n")
+
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	if _, ok := root.Syntax.(*ast.Program); !ok {
+		t.Fatalf("expected the root to be the program, got=%T", root.Syntax)
+	}
+
+	// the span of a statement stops before its semicolon, so the semicolon belongs to the program
+	if len(root.Children) != 3 || root.Children[1].Token.Type != token.SEMICOLON ||
+		root.Children[2].Token.Type != token.EOF {
+		t.Fatalf("expected a statement, its semicolon and the EOF token, got=%d children", len(root.Children))
+	}
+
+	let := root.Children[0]
+
+	if _, ok := let.Syntax.(*ast.LetStatement); !ok {
+		t.Fatalf("expected a let statement, got=%T", let.Syntax)
+	}
+
+	var kinds []string
+
+	for _, child := range let.Children {
+		if child.IsToken() {
+			kinds = append(kinds, child.Token.Raw)
+		} else {
+			kinds = append(kinds, child.Syntax.String())
+		}
+	}
+
+	if got := strings.Join(kinds, "|"); got != "let|x|=|(a + 1)" {
+		t.Errorf("expected the let statement's tokens and parts in order, got=%q", got)
+	}
+
+	if semicolon := root.Children[1].Token; semicolon.Trailing != " // sum" {
+		t.Errorf("expected the comment to trail the semicolon, got=%q", semicolon.Trailing)
+	}
+
+	var raws []string
+
+	for leaf := range let.Tokens() {
+		raws = append(raws, leaf.Token.Raw)
+	}
+
+	if got := strings.Join(raws, " "); got != "let x = a + 1" {
+		t.Errorf("expected every token of the statement, got=%q", got)
+	}
+}
+
+func TestCodemodKeepsFormatting(t *testing.T) {
+	input := "let  total = 0;   // running total\n\nlet f = fn(total) {\n\ttotal + 1 // next\n};\n"
+
+	root, _ := cst.Parse("", input)
+	program := root.Syntax.(*ast.Program)
+
+	// rename the variable bound by the first let, and nothing else
+	name := program.Statements[0].(*ast.LetStatement).Name
+
+	for leaf := range root.Find(name).Tokens() {
+		leaf.Token.Raw = "sum"
+	}
+
+	expected := "let  sum = 0;   // running total\n\nlet f = fn(total) {\n\ttotal + 1 // next\n};\n"
+
+	if got := root.Text(); got != expected {
+		t.Errorf("expected=%q, got=%q", expected, got)
+	}
+}
+
+func TestFindMissingNode(t *testing.T) {
+	root, _ := cst.Parse("", "1")
+
+	if root.Find(&ast.Identifier{}) != nil {
+		t.Errorf("expected no node for syntax outside the tree")
+	}
+}
@@ -0,0 +1,101 @@
+package diagnostic
+
+import (
+	"bytes"
+	"monkeyInterpreter/pkg/diagnostic"
+	"monkeyInterpreter/pkg/token"
+	"testing"
+)
+
+func TestError(t *testing.T) {
+	tests := []struct {
+		diag     diagnostic.Diagnostic
+		expected string
+	}{
+		{
+			diagnostic.Diagnostic{Severity: diagnostic.ERROR, Code: "P001", Message: "boom",
+				Pos: token.Position{Filename: "a.mk", Line: 2, Column: 4, Offset: 9}},
+			"a.mk:2:4: error[P001]: boom",
+		},
+		{
+			diagnostic.Diagnostic{Severity: diagnostic.WARNING, Message: "careful",
+				Pos: token.Position{Line: 1, Column: 1}},
+			"1:1: warning: careful",
+		},
+		{
+			diagnostic.Diagnostic{Severity: diagnostic.NOTE, Message: "no position"},
+			"note: no position",
+		},
+	}
+
+	for _, tt := range tests {
+		if tt.diag.Error() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, tt.diag.Error())
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	source := "let x = 5;\n\tlet y = );\nlet z = 1;"
+
+	diag := diagnostic.Diagnostic{
+		Severity: diagnostic.ERROR,
+		Code:     "P002",
+		Message:  "expected an expression, got ) instead",
+		Pos:      token.Position{Filename: "a.mk", Line: 2, Column: 10, Offset: 20},
+		End:      token.Position{Filename: "a.mk", Line: 2, Column: 11, Offset: 21},
+	}
+
+	expected := "a.mk:2:10: error[P002]: expected an expression, got ) instead\n" +
+		"   2 | \tlet y = );\n" +
+		"     | \t        ^\n"
+
+	var out bytes.Buffer
+	diagnostic.Render(&out, source, diag)
+
+	if out.String() != expected {
+		t.Errorf("expected=%q, got=%q", expected, out.String())
+	}
+}
+
+func TestRenderMultiCharacterSpan(t *testing.T) {
+	source := "foo == bar"
+
+	diag := diagnostic.Diagnostic{
+		Message: "oops",
+		Pos:     token.Position{Line: 1, Column: 5, Offset: 4},
+		End:     token.Position{Line: 1, Column: 7, Offset: 6},
+	}
+
+	expected := "1:5: error: oops\n" +
+		"   1 | foo == bar\n" +
+		"     |     ^^\n"
+
+	var out bytes.Buffer
+	diagnostic.Render(&out, source, diag)
+
+	if out.String() != expected {
+		t.Errorf("expected=%q, got=%q", expected, out.String())
+	}
+}
+
+func TestRenderAfterMultibyteCharacters(t *testing.T) {
+	source := "let 名前 = \"日本\" + ;"
+
+	diag := diagnostic.Diagnostic{
+		Message: "oops",
+		Pos:     token.Position{Line: 1, Column: 10, Offset: 13},
+		End:     token.Position{Line: 1, Column: 14, Offset: 21},
+	}
+
+	expected := "1:10: error: oops\n" +
+		"   1 | let 名前 = \"日本\" + ;\n" +
+		"     |          ^^^^\n"
+
+	var out bytes.Buffer
+	diagnostic.Render(&out, source, diag)
+
+	if out.String() != expected {
+		t.Errorf("expected=%q, got=%q", expected, out.String())
+	}
+}
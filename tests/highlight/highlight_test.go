@@ -0,0 +1,113 @@
+package highlight
+
+import (
+	"bytes"
+	"monkeyInterpreter/pkg/highlight"
+	"monkeyInterpreter/pkg/token"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestClassOf(t *testing.T) {
+	tests := []struct {
+		tokenType token.TokenType
+		expected  highlight.Class
+	}{
+		{token.LET, highlight.CLASS_KEYWORD},
+		{token.FUNCTION, highlight.CLASS_KEYWORD},
+		{token.TRUE, highlight.CLASS_KEYWORD},
+		{token.IDENT, highlight.CLASS_IDENTIFIER},
+		{token.INT, highlight.CLASS_NUMBER},
+		{token.FLOAT, highlight.CLASS_NUMBER},
+		{token.STRING, highlight.CLASS_STRING},
+		{token.EQ, highlight.CLASS_OPERATOR},
+		{token.BANG, highlight.CLASS_OPERATOR},
+		{token.COMMENT, highlight.CLASS_COMMENT},
+		{token.DOC_COMMENT, highlight.CLASS_COMMENT},
+		{token.ILLEGAL, highlight.CLASS_ILLEGAL},
+		{token.SEMICOLON, highlight.CLASS_NONE},
+		{token.LBRACE, highlight.CLASS_NONE},
+	}
+
+	for _, tt := range tests {
+		if class := highlight.ClassOf(tt.tokenType); class != tt.expected {
+			t.Errorf("%s: expected=%q, got=%q", tt.tokenType, tt.expected, class)
+		}
+	}
+}
+
+func TestANSI(t *testing.T) {
+	var got = highlight.Source("let x = 5;", highlight.FORMAT_ANSI)
+	var expected = "\x1b[35mlet\x1b[0m \x1b[34mx\x1b[0m \x1b[33m=\x1b[0m \x1b[36m5\x1b[0m;"
+
+	if got != expected {
+		t.Errorf("expected=%q, got=%q", expected, got)
+	}
+}
+
+func TestComments(t *testing.T) {
+	var got = highlight.Source("x // note", highlight.FORMAT_ANSI)
+	var expected = "\x1b[34mx\x1b[0m \x1b[90m// note\x1b[0m"
+
+	if got != expected {
+		t.Errorf("expected=%q, got=%q", expected, got)
+	}
+}
+
+func TestHTML(t *testing.T) {
+	var got = highlight.Source(`if (a < "<b>") { @ }`, highlight.FORMAT_HTML)
+	var expected = `<pre class="mk-source"><span class="mk-keyword">if</span> (<span class="mk-identifier">a</span> ` +
+		`<span class="mk-operator">&lt;</span> <span class="mk-string">&#34;&lt;b&gt;&#34;</span>) { ` +
+		`<span class="mk-illegal">@</span> }</pre>` + "\n"
+
+	if got != expected {
+		t.Errorf("expected=%q, got=%q", expected, got)
+	}
+}
+
+// TestMarkupOnlyAddsColour checks that stripping the escape sequences from highlighted source always gives back the
+// source, whatever the lexer makes of it
+func TestMarkupOnlyAddsColour(t *testing.T) {
+	var escapes = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+	inputs := []string{
+		"let add = fn(a, b) {\n\treturn a + b;\n};\n",
+		"  \"unterminated\n",
+		"let café = \"é\";",
+		"a\x00b",
+		"",
+	}
+
+	for _, input := range inputs {
+		var highlighted = highlight.Source(input, highlight.FORMAT_ANSI)
+
+		if stripped := escapes.ReplaceAllString(highlighted, ""); stripped != input {
+			t.Errorf("expected %q back, got=%q", input, stripped)
+		}
+
+		if plain := highlight.Source(input, highlight.FORMAT_PLAIN); plain != input {
+			t.Errorf("expected plain format to leave %q alone, got=%q", input, plain)
+		}
+	}
+}
+
+func TestColourDisabled(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+
+	if highlight.ColourEnabled(&bytes.Buffer{}) {
+		t.Errorf("expected no colour for output that is not a terminal")
+	}
+
+	t.Setenv("NO_COLOR", "1")
+
+	if highlight.ColourEnabled(&bytes.Buffer{}) {
+		t.Errorf("expected no colour with NO_COLOR set")
+	}
+}
+
+func TestMultibyteCharactersAreKeptWhole(t *testing.T) {
+	if strings.Contains(highlight.Source("é", highlight.FORMAT_ANSI), "\xc3\x1b") {
+		t.Errorf("expected multibyte characters not to be split by escape sequences")
+	}
+}
@@ -1,9 +1,16 @@
 package lexer
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"monkeyInterpreter/pkg/diagnostic"
 	"monkeyInterpreter/pkg/lexer"
 	"monkeyInterpreter/pkg/token"
+	"runtime"
+	"strings"
 	"testing"
+	"testing/iotest"
 )
 
 func TestNextToken(t *testing.T) {
@@ -16,7 +23,7 @@ let add = fn(x, y) {
 
 let result = add(five, ten);
 
-!-/*5;
+!-/ *5;
 
 5 < 10 > 5;
 
@@ -28,6 +35,11 @@ if (5 < 10) {
 
 10 == 10;
 10 != 9;
+"foobar"
+"foo bar"
+[1, 2];
+{"foo": "bar"}
+a <= b >= c % d && e || f & g | h;
 `
 
 	tests := []struct {
@@ -115,6 +127,39 @@ if (5 < 10) {
 		{token.INT, "9"},
 		{token.SEMICOLON, ";"},
 
+		{token.STRING, "foobar"},
+		{token.STRING, "foo bar"},
+
+		{token.LBRACKET, "["},
+		{token.INT, "1"},
+		{token.COMMA, ","},
+		{token.INT, "2"},
+		{token.RBRACKET, "]"},
+		{token.SEMICOLON, ";"},
+
+		{token.LBRACE, "{"},
+		{token.STRING, "foo"},
+		{token.COLON, ":"},
+		{token.STRING, "bar"},
+		{token.RBRACE, "}"},
+
+		{token.IDENT, "a"},
+		{token.LT_EQ, "<="},
+		{token.IDENT, "b"},
+		{token.GT_EQ, ">="},
+		{token.IDENT, "c"},
+		{token.PERCENT, "%"},
+		{token.IDENT, "d"},
+		{token.AND, "&&"},
+		{token.IDENT, "e"},
+		{token.OR, "||"},
+		{token.IDENT, "f"},
+		{token.ILLEGAL, "&"},
+		{token.IDENT, "g"},
+		{token.ILLEGAL, "|"},
+		{token.IDENT, "h"},
+		{token.SEMICOLON, ";"},
+
 		{token.EOF, ""},
 	}
 
@@ -132,3 +177,674 @@ if (5 < 10) {
 		}
 	}
 }
+
+func TestTokenPositions(t *testing.T) {
+	input := `let x = 5;
+  x == 10;`
+
+	tests := []struct {
+		expectedType  token.TokenType
+		expectedStart token.Position
+		expectedEnd   token.Position
+	}{
+		{token.LET, token.Position{Filename: "test.mk", Line: 1, Column: 1, Offset: 0}, token.Position{Filename: "test.mk", Line: 1, Column: 4, Offset: 3}},
+		{token.IDENT, token.Position{Filename: "test.mk", Line: 1, Column: 5, Offset: 4}, token.Position{Filename: "test.mk", Line: 1, Column: 6, Offset: 5}},
+		{token.ASSIGN, token.Position{Filename: "test.mk", Line: 1, Column: 7, Offset: 6}, token.Position{Filename: "test.mk", Line: 1, Column: 8, Offset: 7}},
+		{token.INT, token.Position{Filename: "test.mk", Line: 1, Column: 9, Offset: 8}, token.Position{Filename: "test.mk", Line: 1, Column: 10, Offset: 9}},
+		{token.SEMICOLON, token.Position{Filename: "test.mk", Line: 1, Column: 10, Offset: 9}, token.Position{Filename: "test.mk", Line: 1, Column: 11, Offset: 10}},
+		{token.IDENT, token.Position{Filename: "test.mk", Line: 2, Column: 3, Offset: 13}, token.Position{Filename: "test.mk", Line: 2, Column: 4, Offset: 14}},
+		{token.EQ, token.Position{Filename: "test.mk", Line: 2, Column: 5, Offset: 15}, token.Position{Filename: "test.mk", Line: 2, Column: 7, Offset: 17}},
+		{token.INT, token.Position{Filename: "test.mk", Line: 2, Column: 8, Offset: 18}, token.Position{Filename: "test.mk", Line: 2, Column: 10, Offset: 20}},
+		{token.SEMICOLON, token.Position{Filename: "test.mk", Line: 2, Column: 10, Offset: 20}, token.Position{Filename: "test.mk", Line: 2, Column: 11, Offset: 21}},
+		{token.EOF, token.Position{Filename: "test.mk", Line: 2, Column: 11, Offset: 21}, token.Position{Filename: "test.mk", Line: 2, Column: 11, Offset: 21}},
+	}
+
+	lex := lexer.NewFile("test.mk", input)
+
+	for i, tt := range tests {
+		tok := lex.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. exepcted=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Pos != tt.expectedStart {
+			t.Errorf("tests[%d] - start position wrong. expected=%+v, got=%+v", i, tt.expectedStart, tok.Pos)
+		}
+
+		if tok.End != tt.expectedEnd {
+			t.Errorf("tests[%d] - end position wrong. expected=%+v, got=%+v", i, tt.expectedEnd, tok.End)
+		}
+	}
+}
+
+func TestStringEscapes(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedLiteral string
+	}{
+		{`"a\nb"`, "a\nb"},
+		{`"tab\there"`, "tab\there"},
+		{`"say \"hi\""`, `say "hi"`},
+		{`"back\\slash"`, `back\slash`},
+		{`"\u{41}\u{e9}\u{1F600}"`, "A\u00e9\U0001F600"},
+		{`""`, ""},
+		{"\"a\x00b\"", "a\x00b"},
+	}
+
+	for _, tt := range tests {
+		lex := lexer.New(tt.input)
+		tok := lex.NextToken()
+
+		if tok.Type != token.STRING {
+			t.Fatalf("%s - tokentype wrong. expected=%q, got=%q", tt.input, token.STRING, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Errorf("%s - literal was wrong. expected=%q, got=%q", tt.input, tt.expectedLiteral, tok.Literal)
+		}
+
+		if len(lex.Errors()) != 0 {
+			t.Errorf("%s - unexpected lexer errors %v", tt.input, lex.Errors())
+		}
+
+		if tok.End.Offset != len(tt.input) {
+			t.Errorf("%s - expected token to end at offset %d, got=%d", tt.input, len(tt.input), tok.End.Offset)
+		}
+	}
+}
+
+func TestStringErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedType    token.TokenType
+		expectedCode    string
+		expectedMessage string
+		expectedPos     string
+	}{
+		{`let s = "abc`, token.ILLEGAL, diagnostic.UNTERMINATED_STRING, "string literal not terminated", "1:9"},
+		{`"ab\q"`, token.STRING, diagnostic.INVALID_ESCAPE, `unknown escape sequence \q`, "1:4"},
+		{`"\u{110000}"`, token.STRING, diagnostic.INVALID_ESCAPE, `\u{110000} is not a valid unicode code point`, "1:2"},
+		{`"\u{}"`, token.STRING, diagnostic.INVALID_ESCAPE, `\u{} is not a valid unicode code point`, "1:2"},
+		{`"\u41"`, token.STRING, diagnostic.INVALID_ESCAPE, `expected { after \u`, "1:2"},
+	}
+
+	for _, tt := range tests {
+		lex := lexer.New(tt.input)
+
+		var tok token.Token
+
+		for tok = lex.NextToken(); tok.Type != tt.expectedType && tok.Type != token.EOF; tok = lex.NextToken() {
+		}
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("%s - expected a %s token", tt.input, tt.expectedType)
+		}
+
+		errors := lex.Errors()
+
+		if len(errors) != 1 {
+			t.Fatalf("%s - expected 1 error, got=%d %v", tt.input, len(errors), errors)
+		}
+
+		if errors[0].Code != tt.expectedCode || errors[0].Message != tt.expectedMessage {
+			t.Errorf("%s - expected %s %q, got=%s %q", tt.input, tt.expectedCode, tt.expectedMessage,
+				errors[0].Code, errors[0].Message)
+		}
+
+		if errors[0].Pos.String() != tt.expectedPos {
+			t.Errorf("%s - expected error at %s, got=%s", tt.input, tt.expectedPos, errors[0].Pos)
+		}
+	}
+}
+
+func TestCommentsAreSkipped(t *testing.T) {
+	input := `// a line comment
+let x /* inline */ = 5; // trailing
+/* outer /* nested */ still outer */
+x / 2`
+
+	expected := []token.TokenType{
+		token.LET, token.IDENT, token.ASSIGN, token.INT, token.SEMICOLON,
+		token.IDENT, token.SLASH, token.INT, token.EOF,
+	}
+
+	lex := lexer.New(input)
+
+	for i, expectedType := range expected {
+		if tok := lex.NextToken(); tok.Type != expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q (%q)", i, expectedType, tok.Type, tok.Literal)
+		}
+	}
+
+	if len(lex.Errors()) != 0 {
+		t.Errorf("unexpected lexer errors %v", lex.Errors())
+	}
+}
+
+func TestCommentsMayHoldNul(t *testing.T) {
+	for _, input := range []string{"/* \x00 */ x", "// \x00 still a comment\nx"} {
+		lex := lexer.New(input)
+
+		if tok := lex.NextToken(); tok.Type != token.IDENT || len(lex.Errors()) != 0 {
+			t.Errorf("%q: expected the NUL to be part of the comment, got=%q, errors=%v", input, tok.Type, lex.Errors())
+		}
+	}
+}
+
+func TestScanComments(t *testing.T) {
+	input := "/// adds one\nlet x = 1; // note\n/** doc */ /* a /* b */ c */ /**/ //// ruler\n"
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+		expectedPos     string
+	}{
+		{token.DOC_COMMENT, "/// adds one", "1:1"},
+		{token.LET, "let", "2:1"},
+		{token.IDENT, "x", "2:5"},
+		{token.ASSIGN, "=", "2:7"},
+		{token.INT, "1", "2:9"},
+		{token.SEMICOLON, ";", "2:10"},
+		{token.COMMENT, "// note", "2:12"},
+		{token.DOC_COMMENT, "/** doc */", "3:1"},
+		{token.COMMENT, "/* a /* b */ c */", "3:12"},
+		{token.COMMENT, "/**/", "3:30"},
+		{token.COMMENT, "//// ruler", "3:35"},
+		{token.EOF, "", "4:1"},
+	}
+
+	lex := lexer.New(input)
+	lex.SetMode(lexer.SCAN_COMMENTS)
+
+	for i, tt := range tests {
+		tok := lex.NextToken()
+
+		if tok.Type != tt.expectedType || tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - expected %s %q, got=%s %q", i, tt.expectedType, tt.expectedLiteral, tok.Type, tok.Literal)
+		}
+
+		if tok.Pos.String() != tt.expectedPos {
+			t.Errorf("tests[%d] - expected position %s, got=%s", i, tt.expectedPos, tok.Pos)
+		}
+	}
+}
+
+func TestUnterminatedComment(t *testing.T) {
+	for _, mode := range []lexer.Mode{0, lexer.SCAN_COMMENTS} {
+		lex := lexer.New("let x = 1; /* open /* nested */ still open")
+		lex.SetMode(mode)
+
+		var tok token.Token
+
+		for tok = lex.NextToken(); tok.Type != token.EOF; tok = lex.NextToken() {
+		}
+
+		errors := lex.Errors()
+
+		if len(errors) != 1 {
+			t.Fatalf("mode %d - expected 1 error, got=%d", mode, len(errors))
+		}
+
+		if errors[0].Code != diagnostic.UNTERMINATED_COMMENT || errors[0].Pos.String() != "1:12" {
+			t.Errorf("mode %d - expected %s at 1:12, got=%s", mode, diagnostic.UNTERMINATED_COMMENT, errors[0])
+		}
+	}
+}
+
+func TestUnicodeIdentifiersAndStrings(t *testing.T) {
+	input := "let größe = \"Grüße\";\nlet 名前 = \"日本語\"; _x1"
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+		expectedPos     string
+		expectedEnd     string
+	}{
+		{token.LET, "let", "1:1", "1:4"},
+		{token.IDENT, "größe", "1:5", "1:10"},
+		{token.ASSIGN, "=", "1:11", "1:12"},
+		{token.STRING, "Grüße", "1:13", "1:20"},
+		{token.SEMICOLON, ";", "1:20", "1:21"},
+		{token.LET, "let", "2:1", "2:4"},
+		{token.IDENT, "名前", "2:5", "2:7"},
+		{token.ASSIGN, "=", "2:8", "2:9"},
+		{token.STRING, "日本語", "2:10", "2:15"},
+		{token.SEMICOLON, ";", "2:15", "2:16"},
+		{token.IDENT, "_x", "2:17", "2:19"},
+		{token.INT, "1", "2:19", "2:20"},
+		{token.EOF, "", "2:20", "2:20"},
+	}
+
+	lex := lexer.New(input)
+
+	for i, tt := range tests {
+		tok := lex.NextToken()
+
+		if tok.Type != tt.expectedType || tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - expected %s %q, got=%s %q", i, tt.expectedType, tt.expectedLiteral, tok.Type, tok.Literal)
+		}
+
+		if tok.Pos.String() != tt.expectedPos || tok.End.String() != tt.expectedEnd {
+			t.Errorf("tests[%d] - expected span %s-%s, got=%s-%s", i, tt.expectedPos, tt.expectedEnd, tok.Pos, tok.End)
+		}
+
+		if tok.Type != token.EOF && input[tok.Pos.Offset:tok.End.Offset] == "" {
+			t.Errorf("tests[%d] - expected offsets to cover the token, got=%d-%d", i, tok.Pos.Offset, tok.End.Offset)
+		}
+	}
+
+	if len(lex.Errors()) != 0 {
+		t.Errorf("unexpected lexer errors %v", lex.Errors())
+	}
+}
+
+func TestNonLetterCharactersAreIllegal(t *testing.T) {
+	lex := lexer.New("a → b")
+
+	for i, expected := range []string{"a", "→", "b"} {
+		tok := lex.NextToken()
+
+		if tok.Literal != expected {
+			t.Fatalf("tests[%d] - expected literal %q, got=%q", i, expected, tok.Literal)
+		}
+
+		if expected == "→" && tok.Type != token.ILLEGAL {
+			t.Errorf("expected %q to be a single ILLEGAL token, got=%s", expected, tok.Type)
+		}
+	}
+}
+
+func TestInvalidUTF8(t *testing.T) {
+	tests := []struct {
+		input        string
+		expectedPos  []string
+		expectedType token.TokenType
+	}{
+		{"let x = \xff;", []string{"1:9"}, token.ILLEGAL},
+		{"\"a\xc3\"", []string{"1:3"}, token.STRING},
+		{"// \xfe\xfe\nx", []string{"1:4", "1:5"}, token.IDENT},
+	}
+
+	for _, tt := range tests {
+		lex := lexer.New(tt.input)
+
+		var found bool
+
+		for tok := lex.NextToken(); tok.Type != token.EOF; tok = lex.NextToken() {
+			found = found || tok.Type == tt.expectedType
+		}
+
+		if !found {
+			t.Errorf("%q - expected a %s token", tt.input, tt.expectedType)
+		}
+
+		errors := lex.Errors()
+
+		if len(errors) != len(tt.expectedPos) {
+			t.Fatalf("%q - expected %d errors, got=%v", tt.input, len(tt.expectedPos), errors)
+		}
+
+		for i, diag := range errors {
+			if diag.Code != diagnostic.INVALID_UTF8 || diag.Pos.String() != tt.expectedPos[i] {
+				t.Errorf("%q - expected %s at %s, got=%s", tt.input, diagnostic.INVALID_UTF8, tt.expectedPos[i], diag)
+			}
+		}
+	}
+}
+
+func TestByteOrderMarkIsSkipped(t *testing.T) {
+	lex := lexer.New("\uFEFFlet x")
+	tok := lex.NextToken()
+
+	if tok.Type != token.LET || tok.Pos.String() != "1:1" || tok.Pos.Offset != 3 {
+		t.Errorf("expected let at 1:1 offset 3, got=%s %q at %s offset %d", tok.Type, tok.Literal, tok.Pos, tok.Pos.Offset)
+	}
+}
+
+func TestNumberLiterals(t *testing.T) {
+	tests := []struct {
+		input        string
+		expectedType token.TokenType
+	}{
+		{"0", token.INT},
+		{"1234567890", token.INT},
+		{"1_000_000", token.INT},
+		{"0x1F", token.INT},
+		{"0XdeadBEEF", token.INT},
+		{"0x_ff_ff", token.INT},
+		{"0o17", token.INT},
+		{"0b1010", token.INT},
+		{"0B1_0", token.INT},
+		{"3.14", token.FLOAT},
+		{"0.5", token.FLOAT},
+		{"1e-9", token.FLOAT},
+		{"2.5E+10", token.FLOAT},
+		{"1_000.000_1", token.FLOAT},
+		{"6e23", token.FLOAT},
+	}
+
+	for _, tt := range tests {
+		lex := lexer.New(tt.input)
+		tok := lex.NextToken()
+
+		if tok.Type != tt.expectedType || tok.Literal != tt.input {
+			t.Errorf("%s - expected %s %q, got=%s %q", tt.input, tt.expectedType, tt.input, tok.Type, tok.Literal)
+		}
+
+		if len(lex.Errors()) != 0 {
+			t.Errorf("%s - unexpected lexer errors %v", tt.input, lex.Errors())
+		}
+
+		if next := lex.NextToken(); next.Type != token.EOF {
+			t.Errorf("%s - expected a single token, also got %s %q", tt.input, next.Type, next.Literal)
+		}
+	}
+}
+
+func TestNumberFollowedByDot(t *testing.T) {
+	lex := lexer.New("1.x")
+
+	for i, expected := range []token.TokenType{token.INT, token.ILLEGAL, token.IDENT} {
+		if tok := lex.NextToken(); tok.Type != expected {
+			t.Errorf("tests[%d] - expected %s, got=%s %q", i, expected, tok.Type, tok.Literal)
+		}
+	}
+}
+
+func TestMalformedNumbers(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedLiteral string
+		expectedMessage string
+		expectedPos     string
+	}{
+		{"0x", "0x", "hexadecimal literal has no digits", "1:1"},
+		{"0b;", "0b", "binary literal has no digits", "1:1"},
+		{"1__0", "1__0", "'_' must separate successive digits", "1:3"},
+		{"1_", "1_", "'_' must separate successive digits", "1:2"},
+		{"0x_", "0x_", "hexadecimal literal has no digits", "1:1"},
+		{"0b1023", "0b1023", `invalid digit '2' in binary literal`, "1:5"},
+		{"1_.5", "1_.5", "'_' must separate successive digits", "1:2"},
+		{"0b102", "0b102", `invalid digit '2' in binary literal`, "1:5"},
+		{"0o78", "0o78", `invalid digit '8' in octal literal`, "1:4"},
+		{"1e", "1e", "exponent has no digits", "1:1"},
+		{"2.5e+", "2.5e+", "exponent has no digits", "1:1"},
+		{"017", "017", "decimal literal 017 has a leading zero, use the 0o prefix for octal", "1:1"},
+	}
+
+	for _, tt := range tests {
+		lex := lexer.New(tt.input)
+		tok := lex.NextToken()
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Errorf("%s - expected literal %q, got=%q", tt.input, tt.expectedLiteral, tok.Literal)
+		}
+
+		errors := lex.Errors()
+
+		if len(errors) != 1 {
+			t.Fatalf("%s - expected 1 error, got=%v", tt.input, errors)
+		}
+
+		if errors[0].Code != diagnostic.INVALID_NUMBER || errors[0].Message != tt.expectedMessage {
+			t.Errorf("%s - expected %s %q, got=%s", tt.input, diagnostic.INVALID_NUMBER, tt.expectedMessage, errors[0])
+		}
+
+		if errors[0].Pos.String() != tt.expectedPos {
+			t.Errorf("%s - expected error at %s, got=%s", tt.input, tt.expectedPos, errors[0].Pos)
+		}
+	}
+}
+
+func TestReaderMatchesString(t *testing.T) {
+	var input strings.Builder
+
+	input.WriteString("/* generated */\nlet data = {\n")
+
+	for i := 0; i < 2000; i++ {
+		fmt.Fprintf(&input, "  \"clé_%d\": [%d, 0x%X, %d.5, \"日本 %d\"], // entry\n", i, i, i, i, i)
+	}
+
+	input.WriteString("};\nlen(data) >= 2_000 && \"unterminated")
+
+	readers := map[string]func(string) io.Reader{
+		"whole":    func(s string) io.Reader { return strings.NewReader(s) },
+		"one byte": func(s string) io.Reader { return iotest.OneByteReader(strings.NewReader(s)) },
+		"half":     func(s string) io.Reader { return iotest.HalfReader(strings.NewReader(s)) },
+	}
+
+	for name, newReader := range readers {
+		expected := lexer.NewFile("data.mk", input.String())
+		actual := lexer.NewFileReader("data.mk", newReader(input.String()))
+
+		for i := 0; ; i++ {
+			want, got := expected.NextToken(), actual.NextToken()
+
+			if got != want {
+				t.Fatalf("%s: tokens[%d] - expected=%+v, got=%+v", name, i, want, got)
+			}
+
+			if want.Type == token.EOF {
+				break
+			}
+		}
+
+		if fmt.Sprint(actual.Errors()) != fmt.Sprint(expected.Errors()) {
+			t.Errorf("%s: expected errors %v, got=%v", name, expected.Errors(), actual.Errors())
+		}
+	}
+}
+
+// generator produces size bytes of a hash literal on demand, so the test itself doesn't hold the input in memory
+type generator struct {
+	size    int
+	written int
+	pending []byte
+}
+
+func (g *generator) Read(p []byte) (int, error) {
+	if len(g.pending) == 0 {
+		switch {
+		case g.written == 0:
+			g.pending = []byte("{\n")
+		case g.written < g.size:
+			g.pending = []byte(fmt.Sprintf("  \"key%d\": %d,\n", g.written, g.written))
+		case g.written >= g.size:
+			return 0, io.EOF
+		}
+	}
+
+	n := copy(p, g.pending)
+	g.pending = g.pending[n:]
+	g.written += n
+
+	return n, nil
+}
+
+func TestReaderUsesBoundedMemory(t *testing.T) {
+	const size = 16 << 20
+
+	lex := lexer.NewReader(&generator{size: size})
+
+	var before, during runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	var tokens = 0
+
+	for tok := lex.NextToken(); tok.Type != token.EOF; tok = lex.NextToken() {
+		tokens += 1
+
+		if tokens == 1_000_000 {
+			runtime.GC()
+			runtime.ReadMemStats(&during)
+		}
+	}
+
+	if during.HeapAlloc == 0 {
+		t.Fatalf("expected more than a million tokens, got=%d", tokens)
+	}
+
+	if grown := int64(during.HeapAlloc) - int64(before.HeapAlloc); grown > 4<<20 {
+		t.Errorf("expected lexing to use a small buffer, heap grew by %d bytes", grown)
+	}
+}
+
+func TestReaderErrors(t *testing.T) {
+	reader := io.MultiReader(strings.NewReader("let x = 1"), iotest.ErrReader(errors.New("connection reset")))
+	lex := lexer.NewReader(reader)
+
+	var types []token.TokenType
+
+	for tok := lex.NextToken(); tok.Type != token.EOF; tok = lex.NextToken() {
+		types = append(types, tok.Type)
+	}
+
+	if len(types) != 4 {
+		t.Errorf("expected the tokens read before the error, got=%v", types)
+	}
+
+	errs := lex.Errors()
+
+	if len(errs) != 1 || errs[0].Code != diagnostic.READ_FAILED || errs[0].Pos.String() != "1:10" {
+		t.Fatalf("expected a read error at 1:10, got=%v", errs)
+	}
+
+	if !strings.Contains(errs[0].Message, "connection reset") {
+		t.Errorf("expected the reader's error in the message, got=%q", errs[0].Message)
+	}
+}
+
+func TestTokensIterator(t *testing.T) {
+	lex := lexer.New("let x = 1;")
+
+	var types []token.TokenType
+
+	for tok := range lex.Tokens() {
+		types = append(types, tok.Type)
+	}
+
+	expected := []token.TokenType{token.LET, token.IDENT, token.ASSIGN, token.INT, token.SEMICOLON, token.EOF}
+
+	if fmt.Sprint(types) != fmt.Sprint(expected) {
+		t.Errorf("expected=%v, got=%v", expected, types)
+	}
+
+	// stopping early leaves the rest of the tokens to be read
+	lex = lexer.New("a b c")
+
+	for tok := range lex.Tokens() {
+		if tok.Literal == "b" {
+			break
+		}
+	}
+
+	if tok := lex.NextToken(); tok.Literal != "c" {
+		t.Errorf("expected the lexer to carry on after the last token taken, got=%q", tok.Literal)
+	}
+}
+
+func TestTrivia(t *testing.T) {
+	input := "\uFEFF// greeting\nlet x = \"a\\tb\";  // the value\n\n  /* block\n */ x\t\n"
+
+	lex := lexer.New(input)
+	lex.SetMode(lexer.SCAN_TRIVIA)
+
+	tests := []struct {
+		expectedType     token.TokenType
+		expectedLeading  string
+		expectedRaw      string
+		expectedTrailing string
+	}{
+		{token.LET, "\uFEFF// greeting\n", "let", " "},
+		{token.IDENT, "", "x", " "},
+		{token.ASSIGN, "", "=", " "},
+		{token.STRING, "", "\"a\\tb\"", ""},
+		{token.SEMICOLON, "", ";", "  // the value"},
+		{token.IDENT, "\n\n  /* block\n */ ", "x", "\t"},
+		{token.EOF, "\n", "", ""},
+	}
+
+	var rebuilt strings.Builder
+
+	for i, tt := range tests {
+		tok := lex.NextToken()
+
+		if tok.Type != tt.expectedType || tok.Leading != tt.expectedLeading || tok.Raw != tt.expectedRaw ||
+			tok.Trailing != tt.expectedTrailing {
+			t.Errorf("tests[%d] - expected=%s %q %q %q, got=%s %q %q %q", i, tt.expectedType, tt.expectedLeading,
+				tt.expectedRaw, tt.expectedTrailing, tok.Type, tok.Leading, tok.Raw, tok.Trailing)
+		}
+
+		rebuilt.WriteString(tok.Leading + tok.Raw + tok.Trailing)
+	}
+
+	if rebuilt.String() != input {
+		t.Errorf("expected the trivia to rebuild the input, got=%q", rebuilt.String())
+	}
+}
+
+func TestTriviaRebuildsAnyInput(t *testing.T) {
+	inputs := []string{
+		"",
+		"   \r\n\t",
+		"let a = [1, 2][0] /* nested /* comment */ */ + 0x_1;",
+		"\"unterminated\n let x",
+		"a \x00 b \xff\xfe c",
+		"/* never closed",
+		"fn(x) { x % 2 == 0 || x >= 1.5e3 } // last line without a newline",
+	}
+
+	for _, input := range inputs {
+		lexers := []*lexer.Lexer{lexer.New(input), lexer.NewReader(iotest.OneByteReader(strings.NewReader(input)))}
+
+		for _, lex := range lexers {
+			lex.SetMode(lexer.SCAN_TRIVIA | lexer.SCAN_COMMENTS)
+
+			var rebuilt strings.Builder
+
+			for tok := range lex.Tokens() {
+				if tok.Type == token.COMMENT || tok.Type == token.DOC_COMMENT {
+					t.Errorf("expected comments to be trivia, got=%q", tok.Raw)
+				}
+
+				rebuilt.WriteString(tok.Leading + tok.Raw + tok.Trailing)
+			}
+
+			if rebuilt.String() != input {
+				t.Errorf("expected=%q, got=%q", input, rebuilt.String())
+			}
+		}
+	}
+}
+
+func TestNulIsNotEndOfInput(t *testing.T) {
+	lex := lexer.New("a\x00b")
+
+	expected := []token.TokenType{token.IDENT, token.ILLEGAL, token.IDENT, token.EOF}
+
+	for i, tokenType := range expected {
+		if tok := lex.NextToken(); tok.Type != tokenType {
+			t.Errorf("tests[%d] - expected=%q, got=%q", i, tokenType, tok.Type)
+		}
+	}
+}
+
+func TestTriviaFromMidway(t *testing.T) {
+	lex := lexer.New("let x = 1; // one\nx")
+
+	for i := 0; i < 4; i++ {
+		lex.NextToken()
+	}
+
+	lex.SetMode(lexer.SCAN_TRIVIA)
+
+	if tok := lex.NextToken(); tok.Leading != "" || tok.Raw != ";" || tok.Trailing != " // one" {
+		t.Errorf("expected the trivia around ;, got=%q %q %q", tok.Leading, tok.Raw, tok.Trailing)
+	}
+
+	if tok := lex.NextToken(); tok.Leading != "\n" || tok.Raw != "x" {
+		t.Errorf("expected the trivia around x, got=%q %q %q", tok.Leading, tok.Raw, tok.Trailing)
+	}
+}
@@ -0,0 +1,280 @@
+package lineedit
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"monkeyInterpreter/pkg/lineedit"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// readLines feeds keys to a new editor and returns every line it reads before the keys run out
+func readLines(t *testing.T, editor *lineedit.Editor) []string {
+	t.Helper()
+
+	var lines []string
+
+	for {
+		line, err := editor.ReadLine("> ")
+
+		if err == io.EOF {
+			return lines
+		}
+
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		lines = append(lines, line)
+	}
+}
+
+func TestEditingKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		keys     string
+		expected string
+	}{
+		{"plain", "let x = 1;\r", "let x = 1;"},
+		{"backspace", "lex\x7ft\r", "let"},
+		{"ctrl-a inserts at the start", "et\x01l\r", "let"},
+		{"ctrl-e moves to the end", "le\x01\x05t\r", "let"},
+		{"ctrl-b and ctrl-f", "lt\x02e\x06;\r", "let;"},
+		{"arrow keys", "lt\x1b[De\x1b[C;\r", "let;"},
+		{"home and end", "et\x1b[Hl\x1b[F;\r", "let;"},
+		{"delete key", "lxet\x01\x1b[C\x1b[3~\r", "let"},
+		{"ctrl-w deletes a word", "let foo  \x17x\r", "let x"},
+		{"ctrl-u deletes to the start", "foo bar\x02\x02\x02\x15x\r", "xbar"},
+		{"ctrl-k deletes to the end", "foo bar\x01\x06\x06\x06\x0b\r", "foo"},
+		{"ctrl-d deletes under the cursor", "lxet\x01\x06\x04\r", "let"},
+		{"line feed ends the line", "let\n", "let"},
+	}
+
+	for _, tt := range tests {
+		var editor = lineedit.New(strings.NewReader(tt.keys), io.Discard)
+		line, err := editor.ReadLine("> ")
+
+		if err != nil {
+			t.Fatalf("%s: unexpected error %v", tt.name, err)
+		}
+
+		if line != tt.expected {
+			t.Errorf("%s: expected=%q, got=%q", tt.name, tt.expected, line)
+		}
+	}
+}
+
+func TestEndOfInput(t *testing.T) {
+	var editor = lineedit.New(strings.NewReader("\x04"), io.Discard)
+
+	if _, err := editor.ReadLine("> "); err != io.EOF {
+		t.Errorf("expected ctrl-d on an empty line to give io.EOF, got %v", err)
+	}
+
+	editor = lineedit.New(strings.NewReader("let"), io.Discard)
+
+	if line, err := editor.ReadLine("> "); line != "let" || err != nil {
+		t.Errorf("expected a partial line to be returned at the end of input, got %q, %v", line, err)
+	}
+}
+
+func TestInterrupt(t *testing.T) {
+	var editor = lineedit.New(strings.NewReader("let x\x03y\r"), io.Discard)
+
+	if _, err := editor.ReadLine("> "); err != lineedit.ErrInterrupted {
+		t.Fatalf("expected ctrl-c to give ErrInterrupted, got %v", err)
+	}
+
+	if line, _ := editor.ReadLine("> "); line != "y" {
+		t.Errorf("expected the interrupted line to be discarded, got %q", line)
+	}
+}
+
+func TestHistoryBrowsing(t *testing.T) {
+	var keys = "one\rtwo\r\x1b[A\x1b[A\r\x10\x10\x10\x0e\r\x1b[B\rpartial\x1b[A\x1b[A\x1b[B\x1b[B\r"
+	var editor = lineedit.New(strings.NewReader(keys), io.Discard)
+
+	expected := []string{"one", "two", "one", "two", "", "partial"}
+	lines := readLines(t, editor)
+
+	if strings.Join(lines, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected=%q, got=%q", expected, lines)
+	}
+}
+
+func TestReverseSearch(t *testing.T) {
+	var keys = "let apple = 1;\rlet banana = 2;\rapple\r\x12let\r\x12app\r\x12let\x12\x06\r"
+	var editor = lineedit.New(strings.NewReader(keys), io.Discard)
+
+	expected := []string{"let apple = 1;", "let banana = 2;", "apple", "let banana = 2;", "apple", "let apple = 1;"}
+	lines := readLines(t, editor)
+
+	if strings.Join(lines, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected=%q, got=%q", expected, lines)
+	}
+}
+
+func TestReverseSearchHandsOnTheKeyThatEndsIt(t *testing.T) {
+	var keys = "let alpha = 1\r\x12alp\x1b[Dx\r\x12alp\x01y\r\x12alp\x1bz\r"
+	var editor = lineedit.New(strings.NewReader(keys), io.Discard)
+
+	expected := []string{"let alpha = 1", "let alpha = x1", "ylet alpha = x1", "z"}
+	lines := readLines(t, editor)
+
+	if strings.Join(lines, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected=%q, got=%q", expected, lines)
+	}
+}
+
+func TestCursorOverWideCharacters(t *testing.T) {
+	var out bytes.Buffer
+	var editor = lineedit.New(strings.NewReader("日本語\x1b[D\x1b[D\r"), &out)
+
+	editor.ReadLine("> ")
+
+	if !strings.Contains(out.String(), "> 日本語\x1b[K\x1b[4D") {
+		t.Errorf("expected the cursor to move back two cells for each character, got %q", out.String())
+	}
+}
+
+func TestCompletion(t *testing.T) {
+	var words = []string{"len", "let", "first", "fn"}
+	var complete = func(word string) []string {
+		var matches []string
+
+		for _, w := range words {
+			if strings.HasPrefix(w, word) {
+				matches = append(matches, w)
+			}
+		}
+
+		return matches
+	}
+
+	tests := []struct {
+		keys     string
+		expected string
+		listed   string
+	}{
+		{"fi\t\r", "first ", ""},
+		{"le\tn\r", "len", "len  let"},
+		{"x = f\t\r", "x = f", "first  fn"},
+		{"zz\t\r", "zz", ""},
+	}
+
+	for _, tt := range tests {
+		var out bytes.Buffer
+		var editor = lineedit.New(strings.NewReader(tt.keys), &out)
+		editor.Complete = complete
+
+		line, _ := editor.ReadLine("> ")
+
+		if line != tt.expected {
+			t.Errorf("%q: expected=%q, got=%q", tt.keys, tt.expected, line)
+		}
+
+		if tt.listed != "" && !strings.Contains(out.String(), "\n"+tt.listed+"\n") {
+			t.Errorf("%q: expected the candidates %q to be listed, got %q", tt.keys, tt.listed, out.String())
+		}
+	}
+}
+
+func TestHistoryFile(t *testing.T) {
+	var path = filepath.Join(t.TempDir(), "history")
+
+	history, err := lineedit.LoadHistory(path)
+
+	if err != nil || history.Len() != 0 {
+		t.Fatalf("expected a missing file to load as an empty history, got %d entries, %v", history.Len(), err)
+	}
+
+	for _, line := range []string{"one", "one", "  ", "two"} {
+		history.Add(line)
+	}
+
+	contents, _ := os.ReadFile(path)
+
+	if string(contents) != "one\ntwo\n" {
+		t.Errorf("expected blank and repeated lines to be skipped, got %q", contents)
+	}
+
+	reloaded, _ := lineedit.LoadHistory(path)
+
+	if reloaded.Len() != 2 || reloaded.At(1) != "two" {
+		t.Errorf("expected the history to be reloaded, got %d entries", reloaded.Len())
+	}
+}
+
+func TestHistoryIsBounded(t *testing.T) {
+	var path = filepath.Join(t.TempDir(), "history")
+	var history = lineedit.NewHistory(path)
+
+	var lines = 0
+	var rewrites = 0
+
+	for i := 0; i < 3*lineedit.MaxHistory; i++ {
+		history.Add(fmt.Sprint("line ", i))
+
+		data, _ := os.ReadFile(path)
+		count := strings.Count(string(data), "\n")
+
+		// a file holding fewer lines than before has been rewritten rather than appended to
+		if count < lines {
+			rewrites += 1
+		}
+
+		if count >= 2*lineedit.MaxHistory {
+			t.Fatalf("expected the file to stay under %d lines, got=%d", 2*lineedit.MaxHistory, count)
+		}
+
+		lines = count
+	}
+
+	if rewrites != 2 {
+		t.Errorf("expected the file to be rewritten once every %d lines, got=%d rewrites", lineedit.MaxHistory, rewrites)
+	}
+
+	if history.Len() != lineedit.MaxHistory {
+		t.Errorf("expected %d entries, got %d", lineedit.MaxHistory, history.Len())
+	}
+
+	reloaded, _ := lineedit.LoadHistory(path)
+
+	if reloaded.Len() != lineedit.MaxHistory || reloaded.At(reloaded.Len()-1) != history.At(history.Len()-1) {
+		t.Errorf("expected the file to be bounded and end with the newest entry, got %d entries", reloaded.Len())
+	}
+}
+
+func TestHistoryKeepsLinesFromOtherSessions(t *testing.T) {
+	var path = filepath.Join(t.TempDir(), "history")
+	var sessions = make([]*lineedit.History, 5)
+	var added []string
+
+	for s := range sessions {
+		sessions[s] = lineedit.NewHistory(path)
+	}
+
+	// between them the sessions add enough lines to have the file rewritten a few times
+	for i := 0; i < lineedit.MaxHistory; i++ {
+		for s, session := range sessions {
+			line := fmt.Sprintf("session %d line %d", s, i)
+			session.Add(line)
+			added = append(added, line)
+		}
+	}
+
+	data, _ := os.ReadFile(path)
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+
+	if len(lines) >= 2*lineedit.MaxHistory {
+		t.Fatalf("expected the file to stay under %d lines, got=%d", 2*lineedit.MaxHistory, len(lines))
+	}
+
+	if expected := added[len(added)-len(lines):]; !reflect.DeepEqual(lines, expected) {
+		t.Errorf("expected the file to hold the newest lines of every session, got=%q", lines[len(lines)-10:])
+	}
+}
@@ -2,9 +2,15 @@ package parser
 
 import (
 	"fmt"
+	"iter"
 	"monkeyInterpreter/pkg/ast"
+	"monkeyInterpreter/pkg/diagnostic"
 	"monkeyInterpreter/pkg/lexer"
 	"monkeyInterpreter/pkg/parser"
+	"monkeyInterpreter/pkg/token"
+	"runtime"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -15,7 +21,7 @@ func TestLetStatements(t *testing.T) {
 		expectedValue      interface{}
 	}{
 		{"let x = 5;", "x", 5},
-		//{"let y = 10;", "y", true},
+		{"let y = true;", "y", true},
 		{"let foobar = y;", "foobar", "y"},
 	}
 
@@ -77,6 +83,34 @@ func TestReturnStatements(t *testing.T) {
 	}
 }
 
+func TestReturnStatementWithoutSemicolon(t *testing.T) {
+	tests := []struct {
+		input              string
+		expectedStatements int
+		expected           string
+	}{
+		{"return 5", 1, "return 5;"},
+		{"return x + y", 1, "return (x + y);"},
+		{"return 5\nreturn 6", 2, "return 5;return 6;"},
+		{"return 5 let x = 1", 2, "return 5;let x = 1;"},
+	}
+
+	for _, tt := range tests {
+		lex := lexer.New(tt.input)
+		parse := parser.New(lex)
+		program := parse.ParseProgram()
+		checkParserErrors(t, parse)
+
+		if len(program.Statements) != tt.expectedStatements {
+			t.Fatalf("%q: expected %d statements, got=%d", tt.input, tt.expectedStatements, len(program.Statements))
+		}
+
+		if program.String() != tt.expected {
+			t.Errorf("%q: expected=%q, got=%q", tt.input, tt.expected, program.String())
+		}
+	}
+}
+
 func TestIdentifierExpression(t *testing.T) {
 	input := "foobar;"
 
@@ -145,6 +179,51 @@ func TestIntegerLiteralExpression(t *testing.T) {
 	}
 }
 
+func TestNumberLiteralExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"0x1F", int64(31)},
+		{"0o17", int64(15)},
+		{"0b1010", int64(10)},
+		{"1_000_000", int64(1000000)},
+		{"9223372036854775807", int64(9223372036854775807)},
+		{"3.14", 3.14},
+		{"1e-9", 1e-9},
+		{"1_000.5", 1000.5},
+	}
+
+	for _, tt := range tests {
+		lex := lexer.New(tt.input)
+		parsr := parser.New(lex)
+
+		program := parsr.ParseProgram()
+		checkParserErrors(t, parsr)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+
+		switch expected := tt.expected.(type) {
+		case int64:
+			literal, ok := stmt.Expression.(*ast.IntegerLiteral)
+
+			if !ok || literal.Value != expected {
+				t.Errorf("%s: expected integer %d, got=%T %v", tt.input, expected, stmt.Expression, stmt.Expression)
+			}
+		case float64:
+			literal, ok := stmt.Expression.(*ast.FloatLiteral)
+
+			if !ok || literal.Value != expected {
+				t.Errorf("%s: expected float %v, got=%T %v", tt.input, expected, stmt.Expression, stmt.Expression)
+			}
+		}
+
+		if program.String() != tt.input {
+			t.Errorf("%s: expected the literal to be kept as written, got=%q", tt.input, program.String())
+		}
+	}
+}
+
 func TestParsingPrefixExpressions(t *testing.T) {
 	prefixTests := []struct {
 		input    string
@@ -154,11 +233,10 @@ func TestParsingPrefixExpressions(t *testing.T) {
 		{"!5;", "!", 5},
 		{"-15;", "-", 15},
 		{"-10;", "-", 10},
-		//{"!true;", "!", true},
-		//{"!false;", "!", false},
+		{"!true;", "!", true},
+		{"!false;", "!", false},
 		{"!foobar;", "!", "foobar"},
 		{"-foobar;", "-", "foobar"},
-		//{"-(5 + 5);", "-", "5 + 5"},
 	}
 
 	for _, tt := range prefixTests {
@@ -218,6 +296,9 @@ func TestParsingInfixExpressions(t *testing.T) {
 		{"foobar < barfoo;", "foobar", "<", "barfoo"},
 		{"foobar == barfoo;", "foobar", "==", "barfoo"},
 		{"foobar != barfoo;", "foobar", "!=", "barfoo"},
+		{"true == true", true, "==", true},
+		{"true != false", true, "!=", false},
+		{"false == false", false, "==", false},
 	}
 
 	for _, tt := range infixTests {
@@ -260,6 +341,30 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 		{"5 > 4 == 3 < 4", "((5 > 4) == (3 < 4))"},
 		{"5 < 4 != 3 > 4", "((5 < 4) != (3 > 4))"},
 		{"3 + 4 * 5 == 3 * 1 + 4 * 5", "((3 + (4 * 5)) == ((3 * 1) + (4 * 5)))"},
+		{"true", "true"},
+		{"false", "false"},
+		{"3 > 5 == false", "((3 > 5) == false)"},
+		{"3 < 5 == true", "((3 < 5) == true)"},
+		{"1 + (2 + 3) + 4", "((1 + (2 + 3)) + 4)"},
+		{"(5 + 5) * 2", "((5 + 5) * 2)"},
+		{"2 / (5 + 5)", "(2 / (5 + 5))"},
+		{"-(5 + 5)", "(-(5 + 5))"},
+		{"!(true == true)", "(!(true == true))"},
+		{"a + add(b * c) + d", "((a + add((b * c))) + d)"},
+		{"add(a, b, 1, 2 * 3, 4 + 5, add(6, 7 * 8))", "add(a, b, 1, (2 * 3), (4 + 5), add(6, (7 * 8)))"},
+		{"add(a + b + c * d / f + g)", "add((((a + b) + ((c * d) / f)) + g))"},
+		{"f(1)(2)", "f(1)(2)"},
+		{"fn(x) { x }(5)", "fn(x) x(5)"},
+		{"a * [1, 2, 3, 4][b * c] * d", "((a * ([1, 2, 3, 4][(b * c)])) * d)"},
+		{"add(a * b[2], b[1], 2 * [1, 2][1])", "add((a * (b[2])), (b[1]), (2 * ([1, 2][1])))"},
+		{"f(1)[0]", "(f(1)[0])"},
+		{"a <= b == b >= a", "((a <= b) == (b >= a))"},
+		{"a + b % c", "(a + (b % c))"},
+		{"a >= min && a <= max", "((a >= min) && (a <= max))"},
+		{"a || b && c", "(a || (b && c))"},
+		{"a && b || c && d", "((a && b) || (c && d))"},
+		{"a == b && !c", "((a == b) && (!c))"},
+		{"a || b || c", "((a || b) || c)"},
 	}
 
 	for _, tt := range tests {
@@ -277,6 +382,740 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 	}
 }
 
+func TestBooleanExpression(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedBoolean bool
+	}{
+		{"true;", true},
+		{"false;", false},
+	}
+
+	for _, tt := range tests {
+		lex := lexer.New(tt.input)
+		parsr := parser.New(lex)
+
+		program := parsr.ParseProgram()
+		checkParserErrors(t, parsr)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program does not have enough statements, got=%d", len(program.Statements))
+		}
+
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+
+		if !ok {
+			t.Fatalf("prgram.Statements[0] is not ast.ExpressionStatement. got=%T", program.Statements[0])
+		}
+
+		if !testBooleanLiteral(t, stmt.Expression, tt.expectedBoolean) {
+			return
+		}
+	}
+}
+
+func TestIfExpression(t *testing.T) {
+	input := `if (x < y) { x }`
+
+	lex := lexer.New(input)
+	parsr := parser.New(lex)
+
+	program := parsr.ParseProgram()
+	checkParserErrors(t, parsr)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program does not have enough statements, got=%d", len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+
+	if !ok {
+		t.Fatalf("prgram.Statements[0] is not ast.ExpressionStatement. got=%T", program.Statements[0])
+	}
+
+	exp, ok := stmt.Expression.(*ast.IfExpression)
+
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.IfExpression. got=%T", stmt.Expression)
+	}
+
+	if !testInfixExpression(t, exp.Condition, "x", "<", "y") {
+		return
+	}
+
+	if len(exp.Consequence.Statements) != 1 {
+		t.Errorf("consequence is not 1 statement. got=%d", len(exp.Consequence.Statements))
+	}
+
+	consequence, ok := exp.Consequence.Statements[0].(*ast.ExpressionStatement)
+
+	if !ok {
+		t.Fatalf("Statements[0] is not ast.ExpressionStatement. got=%T", exp.Consequence.Statements[0])
+	}
+
+	if !testIdentifier(t, consequence.Expression, "x") {
+		return
+	}
+
+	if exp.Alternative != nil {
+		t.Errorf("exp.Alternative was not nil. got=%+v", exp.Alternative)
+	}
+}
+
+func TestIfElseExpression(t *testing.T) {
+	input := `if (x < y) { x } else { y }`
+
+	lex := lexer.New(input)
+	parsr := parser.New(lex)
+
+	program := parsr.ParseProgram()
+	checkParserErrors(t, parsr)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program does not have enough statements, got=%d", len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+
+	if !ok {
+		t.Fatalf("prgram.Statements[0] is not ast.ExpressionStatement. got=%T", program.Statements[0])
+	}
+
+	exp, ok := stmt.Expression.(*ast.IfExpression)
+
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.IfExpression. got=%T", stmt.Expression)
+	}
+
+	if !testInfixExpression(t, exp.Condition, "x", "<", "y") {
+		return
+	}
+
+	consequence, ok := exp.Consequence.Statements[0].(*ast.ExpressionStatement)
+
+	if !ok {
+		t.Fatalf("Statements[0] is not ast.ExpressionStatement. got=%T", exp.Consequence.Statements[0])
+	}
+
+	if !testIdentifier(t, consequence.Expression, "x") {
+		return
+	}
+
+	if exp.Alternative == nil || len(exp.Alternative.Statements) != 1 {
+		t.Fatalf("exp.Alternative does not have 1 statement. got=%+v", exp.Alternative)
+	}
+
+	alternative, ok := exp.Alternative.Statements[0].(*ast.ExpressionStatement)
+
+	if !ok {
+		t.Fatalf("Statements[0] is not ast.ExpressionStatement. got=%T", exp.Alternative.Statements[0])
+	}
+
+	if !testIdentifier(t, alternative.Expression, "y") {
+		return
+	}
+
+	if stmt.End().String() != "1:28" {
+		t.Errorf("expected if expression to end after the closing brace at 1:28, got=%s", stmt.End())
+	}
+}
+
+func TestFunctionLiteralParsing(t *testing.T) {
+	input := `fn(x, y) { x + y; }`
+
+	lex := lexer.New(input)
+	parsr := parser.New(lex)
+
+	program := parsr.ParseProgram()
+	checkParserErrors(t, parsr)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program does not have enough statements, got=%d", len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+
+	if !ok {
+		t.Fatalf("prgram.Statements[0] is not ast.ExpressionStatement. got=%T", program.Statements[0])
+	}
+
+	function, ok := stmt.Expression.(*ast.FunctionLiteral)
+
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.FunctionLiteral. got=%T", stmt.Expression)
+	}
+
+	if len(function.Parameters) != 2 {
+		t.Fatalf("function literal parameters wrong. expected 2, got=%d", len(function.Parameters))
+	}
+
+	testLiteralExpression(t, function.Parameters[0], "x")
+	testLiteralExpression(t, function.Parameters[1], "y")
+
+	if len(function.Body.Statements) != 1 {
+		t.Fatalf("function.Body.Statements does not have 1 statement. got=%d", len(function.Body.Statements))
+	}
+
+	bodyStmt, ok := function.Body.Statements[0].(*ast.ExpressionStatement)
+
+	if !ok {
+		t.Fatalf("function body stmt is not ast.ExpressionStatement. got=%T", function.Body.Statements[0])
+	}
+
+	testInfixExpression(t, bodyStmt.Expression, "x", "+", "y")
+}
+
+func TestFunctionParameterParsing(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedParams []string
+	}{
+		{input: "fn() {};", expectedParams: []string{}},
+		{input: "fn(x) {};", expectedParams: []string{"x"}},
+		{input: "fn(x, y, z) {};", expectedParams: []string{"x", "y", "z"}},
+	}
+
+	for _, tt := range tests {
+		lex := lexer.New(tt.input)
+		parsr := parser.New(lex)
+
+		program := parsr.ParseProgram()
+		checkParserErrors(t, parsr)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		function := stmt.Expression.(*ast.FunctionLiteral)
+
+		if len(function.Parameters) != len(tt.expectedParams) {
+			t.Errorf("length parameters wrong. expected %d, got=%d", len(tt.expectedParams), len(function.Parameters))
+		}
+
+		for i, ident := range tt.expectedParams {
+			testLiteralExpression(t, function.Parameters[i], ident)
+		}
+	}
+}
+
+func TestCallExpressionParsing(t *testing.T) {
+	input := "add(1, 2 * 3, 4 + 5);"
+
+	lex := lexer.New(input)
+	parsr := parser.New(lex)
+
+	program := parsr.ParseProgram()
+	checkParserErrors(t, parsr)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program does not have enough statements, got=%d", len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+
+	if !ok {
+		t.Fatalf("prgram.Statements[0] is not ast.ExpressionStatement. got=%T", program.Statements[0])
+	}
+
+	exp, ok := stmt.Expression.(*ast.CallExpression)
+
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.CallExpression. got=%T", stmt.Expression)
+	}
+
+	if !testIdentifier(t, exp.Function, "add") {
+		return
+	}
+
+	if len(exp.Arguments) != 3 {
+		t.Fatalf("wrong length of arguments. got=%d", len(exp.Arguments))
+	}
+
+	testLiteralExpression(t, exp.Arguments[0], 1)
+	testInfixExpression(t, exp.Arguments[1], 2, "*", 3)
+	testInfixExpression(t, exp.Arguments[2], 4, "+", 5)
+
+	if stmt.End().String() != "1:21" {
+		t.Errorf("expected call to end after the closing paren at 1:21, got=%s", stmt.End())
+	}
+}
+
+func TestStringLiteralExpression(t *testing.T) {
+	input := `"hello\tworld";`
+
+	lex := lexer.New(input)
+	parsr := parser.New(lex)
+
+	program := parsr.ParseProgram()
+	checkParserErrors(t, parsr)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	literal, ok := stmt.Expression.(*ast.StringLiteral)
+
+	if !ok {
+		t.Fatalf("exp not *ast.StringLiteral. got=%T", stmt.Expression)
+	}
+
+	if literal.Value != "hello\tworld" {
+		t.Errorf("literal.Value not %q. got=%q", "hello\tworld", literal.Value)
+	}
+}
+
+func TestUnterminatedStringDiagnostic(t *testing.T) {
+	input := "let a = 1;\nlet s = \"abc;"
+
+	lex := lexer.New(input)
+	parsr := parser.New(lex)
+	parsr.ParseProgram()
+
+	errors := parsr.Errors()
+
+	if len(errors) != 1 {
+		t.Fatalf("expected 1 error, got=%d %v", len(errors), errors)
+	}
+
+	if errors[0].Code != diagnostic.UNTERMINATED_STRING || errors[0].Pos.String() != "2:9" {
+		t.Errorf("expected unterminated string at 2:9, got=%s", errors[0])
+	}
+}
+
+func TestCommentsAreIgnored(t *testing.T) {
+	input := `/// doubles x
+let double = fn(x) { x * /* two */ 2 }; // done
+double(3)`
+
+	for _, mode := range []lexer.Mode{0, lexer.SCAN_COMMENTS} {
+		lex := lexer.New(input)
+		lex.SetMode(mode)
+		parsr := parser.New(lex)
+
+		program := parsr.ParseProgram()
+		checkParserErrors(t, parsr)
+
+		expected := "let double = fn(x) (x * 2);double(3)"
+
+		if program.String() != expected {
+			t.Errorf("mode %d - expected=%q, got=%q", mode, expected, program.String())
+		}
+	}
+}
+
+func TestParsingArrayLiterals(t *testing.T) {
+	input := "[1, 2 * 2, 3 + 3]"
+
+	lex := lexer.New(input)
+	parsr := parser.New(lex)
+
+	program := parsr.ParseProgram()
+	checkParserErrors(t, parsr)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	array, ok := stmt.Expression.(*ast.ArrayLiteral)
+
+	if !ok {
+		t.Fatalf("exp not ast.ArrayLiteral. got=%T", stmt.Expression)
+	}
+
+	if len(array.Elements) != 3 {
+		t.Fatalf("len(array.Elements) not 3. got=%d", len(array.Elements))
+	}
+
+	testIntegerLiteral(t, array.Elements[0], 1)
+	testInfixExpression(t, array.Elements[1], 2, "*", 2)
+	testInfixExpression(t, array.Elements[2], 3, "+", 3)
+
+	if array.End().String() != "1:18" {
+		t.Errorf("expected array to end after the closing bracket at 1:18, got=%s", array.End())
+	}
+}
+
+func TestParsingIndexExpressions(t *testing.T) {
+	input := "myArray[1 + 1]"
+
+	lex := lexer.New(input)
+	parsr := parser.New(lex)
+
+	program := parsr.ParseProgram()
+	checkParserErrors(t, parsr)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	indexExp, ok := stmt.Expression.(*ast.IndexExpression)
+
+	if !ok {
+		t.Fatalf("exp not *ast.IndexExpression. got=%T", stmt.Expression)
+	}
+
+	if !testIdentifier(t, indexExp.Left, "myArray") {
+		return
+	}
+
+	if !testInfixExpression(t, indexExp.Index, 1, "+", 1) {
+		return
+	}
+}
+
+func TestParsingHashLiterals(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{}`, "{}"},
+		{`{"one": 1, "two": 2, "three": 3}`, "{one: 1, two: 2, three: 3}"},
+		{`{true: 1, 2: "two", x: y + 1}`, "{true: 1, 2: two, x: (y + 1)}"},
+		{`{"one": 0 + 1, "two": 10 - 8,}`, "{one: (0 + 1), two: (10 - 8)}"},
+	}
+
+	for _, tt := range tests {
+		lex := lexer.New(tt.input)
+		parsr := parser.New(lex)
+
+		program := parsr.ParseProgram()
+		checkParserErrors(t, parsr)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+
+		if _, ok := stmt.Expression.(*ast.HashLiteral); !ok {
+			t.Fatalf("exp is not ast.HashLiteral. got=%T", stmt.Expression)
+		}
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestNodeSpans(t *testing.T) {
+	tests := []struct {
+		input       string
+		expectedPos string
+		expectedEnd string
+	}{
+		{"foobar;", "1:1", "1:7"},
+		{"  -a * b", "1:3", "1:9"},
+		{"let x = 1 +\n  22;", "1:1", "2:5"},
+		{"\nreturn x;", "2:1", "2:9"},
+	}
+
+	for _, tt := range tests {
+		lex := lexer.New(tt.input)
+		parsr := parser.New(lex)
+
+		program := parsr.ParseProgram()
+		checkParserErrors(t, parsr)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program does not have expected number of statements.  Expected 1, got=%d",
+				len(program.Statements))
+		}
+
+		stmt := program.Statements[0]
+
+		if stmt.Pos().String() != tt.expectedPos {
+			t.Errorf("%q: expected statement to start at %s, got=%s", tt.input, tt.expectedPos, stmt.Pos())
+		}
+
+		if stmt.End().String() != tt.expectedEnd {
+			t.Errorf("%q: expected statement to end at %s, got=%s", tt.input, tt.expectedEnd, stmt.End())
+		}
+	}
+}
+
+func TestParserDiagnostics(t *testing.T) {
+	tests := []struct {
+		input            string
+		expectedCode     string
+		expectedPos      string
+		expectedExpected string
+		expectedGot      string
+	}{
+		{"let = 5;", diagnostic.UNEXPECTED_TOKEN, "1:5", "IDENT", "="},
+		{"let x 5;", diagnostic.UNEXPECTED_TOKEN, "1:7", "=", "INT"},
+		{"\n  5 + ;", diagnostic.EXPECTED_EXPRESSION, "2:7", "", ""},
+		{"99999999999999999999;", diagnostic.INVALID_INTEGER, "1:1", "", ""},
+		{"0xFFFFFFFFFFFFFFFFF", diagnostic.INVALID_INTEGER, "1:1", "", ""},
+		{"x + 1e400", diagnostic.INVALID_FLOAT, "1:5", "", ""},
+		{"let a = 1__0;", diagnostic.INVALID_NUMBER, "1:11", "", ""},
+		{"let a = 0x + 1;", diagnostic.INVALID_NUMBER, "1:9", "", ""},
+	}
+
+	for _, tt := range tests {
+		lex := lexer.New(tt.input)
+		parsr := parser.New(lex)
+		parsr.ParseProgram()
+
+		errors := parsr.Errors()
+
+		if len(errors) == 0 {
+			t.Fatalf("%q: expected parser errors, got none", tt.input)
+		}
+
+		diag := errors[0]
+
+		if diag.Severity != diagnostic.ERROR {
+			t.Errorf("%q: expected severity error, got=%s", tt.input, diag.Severity)
+		}
+
+		if diag.Code != tt.expectedCode {
+			t.Errorf("%q: expected code %s, got=%s", tt.input, tt.expectedCode, diag.Code)
+		}
+
+		if diag.Pos.String() != tt.expectedPos {
+			t.Errorf("%q: expected diagnostic at %s, got=%s", tt.input, tt.expectedPos, diag.Pos)
+		}
+
+		if string(diag.Expected) != tt.expectedExpected || string(diag.Got) != tt.expectedGot {
+			t.Errorf("%q: expected %q vs %q, got=%q vs %q", tt.input, tt.expectedExpected, tt.expectedGot,
+				diag.Expected, diag.Got)
+		}
+	}
+}
+
+func TestErrorRecovery(t *testing.T) {
+	tests := []struct {
+		input              string
+		expectedPositions  []string
+		expectedStatements string
+	}{
+		{"let = 5; let y = 10; let 7;", []string{"1:5", "1:26"}, "let y = 10;"},
+		{"let x = ;\nlet y = 2;\nreturn ;\nfoo;", []string{"1:9", "3:8"}, "let y = 2;foo"},
+		{"let x = 1 +\nlet y = 2;", []string{"2:1"}, "let y = 2;"},
+		{"5 + ) + ) + );\n7", []string{"1:5"}, "7"},
+		{"let x 5\nlet y 6\nz", []string{"1:7", "2:7"}, "z"},
+		{"if (x) { let = 1; y } z", []string{"1:14"}, "ifx yz"},
+		{"if (x) { 1 + ; 2", []string{"1:14", "1:17"}, ""},
+		{"(1 + 2;\n3", []string{"1:7"}, "3"},
+		{"fn(x, 1) { x }; f(1, 2", []string{"1:7", "1:23"}, ""},
+		{"let f = fn(x) { x +  };\nf(1)", []string{"1:22"}, "let f = fn(x) ;f(1)"},
+		{"let a = [1, 2; a[0", []string{"1:14", "1:19"}, ""},
+		{"let h = {1 2}; h", []string{"1:12"}, "h"},
+	}
+
+	for _, tt := range tests {
+		lex := lexer.New(tt.input)
+		parsr := parser.New(lex)
+		program := parsr.ParseProgram()
+
+		errors := parsr.Errors()
+
+		if len(errors) != len(tt.expectedPositions) {
+			t.Errorf("%q: expected %d errors, got=%d %v", tt.input, len(tt.expectedPositions), len(errors), errors)
+			continue
+		}
+
+		for i, pos := range tt.expectedPositions {
+			if errors[i].Pos.String() != pos {
+				t.Errorf("%q: expected error %d at %s, got=%s", tt.input, i, pos, errors[i].Pos)
+			}
+		}
+
+		if program.String() != tt.expectedStatements {
+			t.Errorf("%q: expected recovered program %q, got=%q", tt.input, tt.expectedStatements, program.String())
+		}
+	}
+}
+
+func TestMaxErrors(t *testing.T) {
+	input := "let; let; let; let; let; let 5;"
+
+	lex := lexer.New(input)
+	parsr := parser.New(lex)
+	parsr.SetMaxErrors(3)
+	parsr.ParseProgram()
+
+	errors := parsr.Errors()
+
+	if len(errors) != 4 {
+		t.Fatalf("expected 3 errors and a note, got=%d %v", len(errors), errors)
+	}
+
+	last := errors[len(errors)-1]
+
+	if last.Severity != diagnostic.NOTE || last.Code != diagnostic.TOO_MANY_ERRORS {
+		t.Errorf("expected the last diagnostic to be a too many errors note, got=%s", last)
+	}
+}
+
+func TestParsingTokensFromSlice(t *testing.T) {
+	tokens := []token.Token{
+		{Type: token.LET, Literal: "let"},
+		{Type: token.IDENT, Literal: "x"},
+		{Type: token.ASSIGN, Literal: "="},
+		{Type: token.INT, Literal: "1"},
+		{Type: token.PLUS, Literal: "+"},
+		{Type: token.INT, Literal: "2"},
+		{Type: token.SEMICOLON, Literal: ";"},
+		{Type: token.COMMENT, Literal: "// ignored"},
+		{Type: token.IDENT, Literal: "x"},
+	}
+
+	parsr := parser.New(parser.FromSlice(tokens))
+	program := parsr.ParseProgram()
+	checkParserErrors(t, parsr)
+
+	if program.String() != "let x = (1 + 2);x" {
+		t.Errorf("expected=%q, got=%q", "let x = (1 + 2);x", program.String())
+	}
+}
+
+func TestSliceSourceEndsWithEOF(t *testing.T) {
+	var end = token.Position{Line: 1, Column: 4, Offset: 3}
+	src := parser.FromSlice([]token.Token{{Type: token.IDENT, Literal: "abc", End: end}})
+
+	src.NextToken()
+
+	for i := 0; i < 3; i++ {
+		if tok := src.NextToken(); tok.Type != token.EOF || tok.Pos != end {
+			t.Errorf("expected EOF at %s, got=%s at %s", end, tok.Type, tok.Pos)
+		}
+	}
+
+	parsr := parser.New(parser.FromSlice([]token.Token{{Type: token.LET, Literal: "let", End: end}}))
+	parsr.ParseProgram()
+
+	if errors := parsr.Errors(); len(errors) != 1 || errors[0].Pos != end {
+		t.Errorf("expected one error at %s, got=%v", end, errors)
+	}
+}
+
+func TestSliceSourceWithoutPositionsReportsEveryError(t *testing.T) {
+	// let = 1; let y 2; );
+	tokens := []token.Token{
+		{Type: token.LET, Literal: "let"},
+		{Type: token.ASSIGN, Literal: "="},
+		{Type: token.INT, Literal: "1"},
+		{Type: token.SEMICOLON, Literal: ";"},
+		{Type: token.LET, Literal: "let"},
+		{Type: token.IDENT, Literal: "y"},
+		{Type: token.INT, Literal: "2"},
+		{Type: token.SEMICOLON, Literal: ";"},
+		{Type: token.RPAREN, Literal: ")"},
+		{Type: token.SEMICOLON, Literal: ";"},
+	}
+
+	parsr := parser.New(parser.FromSlice(tokens))
+	parsr.ParseProgram()
+
+	if errors := parsr.Errors(); len(errors) != 3 {
+		t.Errorf("expected an error for each bad statement, got=%d %v", len(errors), errors)
+	}
+}
+
+func TestMalformedNumbersAreReportedOnce(t *testing.T) {
+	// no lexer has looked at these, so the parser is the one to report them
+	tokens := []token.Token{
+		{Type: token.INT, Literal: "1__0"},
+		{Type: token.SEMICOLON, Literal: ";"},
+		{Type: token.FLOAT, Literal: "1.5e"},
+		{Type: token.SEMICOLON, Literal: ";"},
+	}
+
+	parsr := parser.New(parser.FromSlice(tokens))
+	parsr.ParseProgram()
+
+	errors := parsr.Errors()
+
+	if len(errors) != 2 || errors[0].Code != diagnostic.INVALID_INTEGER || errors[1].Code != diagnostic.INVALID_FLOAT {
+		t.Errorf("expected an error for each malformed number, got=%v", errors)
+	}
+
+	// while the lexer reports them itself
+	parsr = parser.New(lexer.New("1__0; 1.5e;"))
+	parsr.ParseProgram()
+
+	if errors := parsr.Errors(); len(errors) != 2 || errors[0].Code != diagnostic.INVALID_NUMBER ||
+		errors[1].Code != diagnostic.INVALID_NUMBER {
+		t.Errorf("expected only the lexer's errors, got=%v", errors)
+	}
+}
+
+// upperCaseIdentifiers is a preprocessing pass that upper cases every identifier on its way to the parser
+func upperCaseIdentifiers(tokens iter.Seq[token.Token]) iter.Seq[token.Token] {
+	return func(yield func(token.Token) bool) {
+		for tok := range tokens {
+			if tok.Type == token.IDENT {
+				tok.Literal = strings.ToUpper(tok.Literal)
+			}
+
+			if !yield(tok) {
+				return
+			}
+		}
+	}
+}
+
+func TestParsingTokensFromSeq(t *testing.T) {
+	lex := lexer.New("let foo = bar(1); /* done */ foo")
+	lex.SetMode(lexer.SCAN_COMMENTS)
+
+	parsr := parser.New(parser.FromSeq(upperCaseIdentifiers(lex.Tokens())))
+	program := parsr.ParseProgram()
+	checkParserErrors(t, parsr)
+
+	if program.String() != "let FOO = BAR(1);FOO" {
+		t.Errorf("expected=%q, got=%q", "let FOO = BAR(1);FOO", program.String())
+	}
+
+	// a sequence without an EOF token of its own is given one
+	parsr = parser.New(parser.FromSeq(slices.Values([]token.Token{{Type: token.INT, Literal: "7"}})))
+	program = parsr.ParseProgram()
+	checkParserErrors(t, parsr)
+
+	if program.String() != "7" {
+		t.Errorf("expected=%q, got=%q", "7", program.String())
+	}
+}
+
+func TestSeqSourceStoppedAfterEarlyStop(t *testing.T) {
+	input := strings.Repeat("let;\n", 50)
+
+	runtime.GC()
+	before := runtime.NumGoroutine()
+
+	for i := 0; i < 100; i++ {
+		parsr := parser.New(parser.FromSeq(lexer.New(input).Tokens()))
+		parsr.ParseProgram()
+
+		if len(parsr.Errors()) != parser.DefaultMaxErrors+1 {
+			t.Fatalf("expected parsing to stop at the error limit, got=%d errors", len(parsr.Errors()))
+		}
+	}
+
+	runtime.GC()
+
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("expected the iterators to be stopped, goroutines went from %d to %d", before, after)
+	}
+
+	// a stopped source has nothing more to give
+	src := parser.FromSeq(lexer.New("a b").Tokens())
+	src.NextToken()
+	src.(parser.Stopper).Stop()
+
+	if tok := src.NextToken(); tok.Type != token.EOF {
+		t.Errorf("expected EOF once stopped, got=%s", tok.Type)
+	}
+}
+
+func TestSkipComments(t *testing.T) {
+	lex := lexer.New("a /* b */ // c\n/// d\n\"e")
+	lex.SetMode(lexer.SCAN_COMMENTS)
+
+	src := parser.SkipComments(lex)
+
+	var types []token.TokenType
+
+	for tok := src.NextToken(); tok.Type != token.EOF; tok = src.NextToken() {
+		types = append(types, tok.Type)
+	}
+
+	if !slices.Equal(types, []token.TokenType{token.IDENT, token.ILLEGAL}) {
+		t.Errorf("expected the comments to be dropped, got=%v", types)
+	}
+
+	errorSource, ok := src.(parser.ErrorSource)
+
+	if !ok || len(errorSource.Errors()) != 1 || errorSource.Errors()[0].Code != diagnostic.UNTERMINATED_STRING {
+		t.Errorf("expected the lexer's errors to be passed on")
+	}
+}
+
 func checkParserErrors(t *testing.T, parsr *parser.Parser) {
 	errors := parsr.Errors()
 
@@ -345,6 +1184,8 @@ func testLiteralExpression(t *testing.T, expression ast.Expression, expected int
 		return testIntegerLiteral(t, expression, v)
 	case string:
 		return testIdentifier(t, expression, v)
+	case bool:
+		return testBooleanLiteral(t, expression, v)
 	}
 
 	t.Errorf("expression type %T was not handled", expected)
@@ -372,6 +1213,27 @@ func testIntegerLiteral(t *testing.T, il ast.Expression, value int64) bool {
 	return true
 }
 
+func testBooleanLiteral(t *testing.T, exp ast.Expression, value bool) bool {
+	boolean, ok := exp.(*ast.Boolean)
+
+	if !ok {
+		t.Errorf("expected expression to be ast.Boolean, got=%T", exp)
+		return false
+	}
+
+	if boolean.Value != value {
+		t.Errorf("expected boolean value to be %t, got=%t", value, boolean.Value)
+		return false
+	}
+
+	if boolean.TokenLiteral() != fmt.Sprintf("%t", value) {
+		t.Errorf("expected boolean.TokenLiteral to be %t, got=%s", value, boolean.TokenLiteral())
+		return false
+	}
+
+	return true
+}
+
 func testIdentifier(t *testing.T, exp ast.Expression, value string) bool {
 	ident, ok := exp.(*ast.Identifier)
 
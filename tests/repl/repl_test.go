@@ -0,0 +1,208 @@
+package repl
+
+import (
+	"bytes"
+	"monkeyInterpreter/pkg/repl"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEvaluatesEachLine(t *testing.T) {
+	input := "let add = fn(a, b) { a + b };\nadd(2, 3)\n\"mon\" + \"key\"\nlet x = 1;\n"
+
+	var out bytes.Buffer
+	repl.Start(strings.NewReader(input), &out)
+
+	expected := ">> >> 5\n>> monkey\n>> >> "
+
+	if out.String() != expected {
+		t.Errorf("expected=%q, got=%q", expected, out.String())
+	}
+}
+
+func TestBindingsPersistBetweenLines(t *testing.T) {
+	input := "let a = 5;\nlet b = a * 2;\nb\n"
+
+	var out bytes.Buffer
+	repl.Start(strings.NewReader(input), &out)
+
+	if !strings.Contains(out.String(), ">> 10\n") {
+		t.Errorf("expected b to evaluate to 10, got=%q", out.String())
+	}
+}
+
+func TestPutsWritesToTheOutput(t *testing.T) {
+	var out bytes.Buffer
+	repl.Start(strings.NewReader("puts(\"hi\")\n"), &out)
+
+	expected := ">> hi\nnull\n>> "
+
+	if out.String() != expected {
+		t.Errorf("expected=%q, got=%q", expected, out.String())
+	}
+}
+
+func TestPrintsParseErrors(t *testing.T) {
+	input := "let x 5\nlet y = 1;\ny\n"
+
+	var out bytes.Buffer
+	repl.Start(strings.NewReader(input), &out)
+
+	expected := ">> 1:7: error[P001]: expected next token to be =, got INT instead\n" +
+		"   1 | let x 5\n" +
+		"     |       ^\n" +
+		">> >> 1\n>> "
+
+	if out.String() != expected {
+		t.Errorf("expected=%q, got=%q", expected, out.String())
+	}
+}
+
+func TestPrintsRuntimeErrors(t *testing.T) {
+	var out bytes.Buffer
+	repl.Start(strings.NewReader("foo\n"), &out)
+
+	if out.String() != ">> ERROR: identifier not found: foo\n>> " {
+		t.Errorf("unexpected output %q", out.String())
+	}
+}
+
+func TestTokenMode(t *testing.T) {
+	var out bytes.Buffer
+
+	session := repl.New(strings.NewReader("let x = 5;\n"), &out)
+	session.SetMode(repl.MODE_TOKENS)
+	session.Run()
+
+	expected := ">> 1:1\tLET\t\"let\"\n1:5\tIDENT\t\"x\"\n1:7\t=\t\"=\"\n1:9\tINT\t\"5\"\n1:10\t;\t\";\"\n>> "
+
+	if out.String() != expected {
+		t.Errorf("expected=%q, got=%q", expected, out.String())
+	}
+}
+
+func TestMultiLineInput(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let add = fn(a, b) {\n  a + b\n};\nadd(1, 2)\n", ">> .. .. >> 3\n>> "},
+		{"[1,\n2,\n3]\n", ">> .. .. [1, 2, 3]\n>> "},
+		{"1 +\n2\n", ">> .. 3\n>> "},
+		{"{\"a\":\n1}[\"a\"]\n", ">> .. 1\n>> "},
+		{"\"two\nlines\"\n", ">> .. two\nlines\n>> "},
+		{"let x = 1 ==\n1; x\n", ">> .. true\n>> "},
+		{"1 /* a\ncomment */ + 1 // (\n", ">> .. 2\n>> "},
+		{"(1 + 2]\n", ">> 1:7: error[P001]: expected next token to be ), got ] instead\n   1 | (1 + 2]\n     |       ^\n>> "},
+	}
+
+	for _, tt := range tests {
+		var out bytes.Buffer
+		repl.Start(strings.NewReader(tt.input), &out)
+
+		if out.String() != tt.expected {
+			t.Errorf("%q: expected=%q, got=%q", tt.input, tt.expected, out.String())
+		}
+	}
+}
+
+func TestPastedBlock(t *testing.T) {
+	input := `let max = fn(a, b) {
+  if (a > b) {
+    a
+  } else {
+    b
+  }
+};
+let values = [3,
+  9];
+max(values[0], values[1])
+`
+
+	var out bytes.Buffer
+	repl.Start(strings.NewReader(input), &out)
+
+	if !strings.HasSuffix(out.String(), ">> 9\n>> ") {
+		t.Errorf("expected the pasted block to evaluate to 9, got=%q", out.String())
+	}
+}
+
+func TestUnfinishedInputAtEndOfStream(t *testing.T) {
+	var out bytes.Buffer
+	repl.Start(strings.NewReader("let x = (1\n"), &out)
+
+	if !strings.Contains(out.String(), "expected next token to be ), got EOF instead") {
+		t.Errorf("expected the unfinished input to be reported, got=%q", out.String())
+	}
+}
+
+func TestMetaCommands(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lib.mk")
+
+	if err := os.WriteFile(path, []byte("let double = fn(x) { x * 2 };\nlet base = 21;\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{":load " + path + "\ndouble(base)\n", ">> >> 42\n>> "},
+		{"let b = 2;\nlet a = \"x\";\n:env\n", ">> >> >> a = x\nb = 2\n>> "},
+		{"let a = 1;\n:reset\na\n", ">> >> >> ERROR: identifier not found: a\n>> "},
+		{":mode ast\n1 + 2 * 3\n:mode eval\n1 + 2 * 3\n", ">> >> (1 + (2 * 3))\n>> >> 7\n>> "},
+		{":mode tokens\n1\n", ">> >> 1:1\tINT\t\"1\"\n>> "},
+		{":mode\n", ">> mode is eval\n>> "},
+		{"puts(\"hi\")\n:reset\nputs(2)\n", ">> hi\nnull\n>> >> 2\nnull\n>> "},
+		{":mode bogus\n", ">> usage: :mode tokens|ast|eval\n>> "},
+		{":quit\n1\n", ">> "},
+		{":bogus\n", ">> unknown command :bogus, type :help to list the commands\n>> "},
+		{":load\n", ">> usage: :load <file>\n>> "},
+	}
+
+	for _, tt := range tests {
+		var out bytes.Buffer
+		repl.Start(strings.NewReader(tt.input), &out)
+
+		if out.String() != tt.expected {
+			t.Errorf("%q: expected=%q, got=%q", tt.input, tt.expected, out.String())
+		}
+	}
+}
+
+func TestLoadReportsErrorsWithFilename(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "broken.mk")
+
+	if err := os.WriteFile(path, []byte("let x = 1;\nlet = 2;\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	repl.Start(strings.NewReader(":load "+path+"\n"), &out)
+
+	if !strings.Contains(out.String(), path+":2:5: error[P001]") {
+		t.Errorf("expected the error to point into the loaded file, got=%q", out.String())
+	}
+}
+
+func TestHelpListsEveryCommand(t *testing.T) {
+	var out bytes.Buffer
+	repl.Start(strings.NewReader(":help\n"), &out)
+
+	for _, cmd := range []string{":help", ":load", ":env", ":reset", ":mode", ":time", ":quit"} {
+		if !strings.Contains(out.String(), cmd) {
+			t.Errorf("expected :help to list %s, got=%q", cmd, out.String())
+		}
+	}
+}
+
+func TestTimeCommand(t *testing.T) {
+	var out bytes.Buffer
+	repl.Start(strings.NewReader(":time\n1 + 1\n"), &out)
+
+	if !strings.Contains(out.String(), "timing on\n>> parse: ") || !strings.Contains(out.String(), "\neval: ") {
+		t.Errorf("expected parse and eval timings, got=%q", out.String())
+	}
+}